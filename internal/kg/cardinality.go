@@ -0,0 +1,260 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConflictPolicy tells what happens when a triple gives a subject more values for a predicate
+// than its declared cardinality allows.
+type ConflictPolicy string
+
+// The conflict policies of a cardinality declaration.
+const (
+	ConflictReject  ConflictPolicy = "reject"  // The triple is rejected with a *CardinalityError
+	ConflictReplace ConflictPolicy = "replace" // The oldest values of the subject are removed to make room for the new one
+	ConflictFlag    ConflictPolicy = "flag"    // The triple is inserted; the conflict is reported by FindContradictions
+)
+
+// DeclareCardinality declares that a subject may have at most maxValues distinct values for the predicate,
+// and what happens when a triple would exceed it. The same value stored in several graphs counts once.
+// An empty policy rejects such triples. A zero maxValues removes the cardinality of the predicate.
+// Existing triples are not checked; see FindContradictions.
+func (kg *KG) DeclareCardinality(predicate string, maxValues int, onConflict ConflictPolicy) error {
+	if predicate == "" {
+		return fmt.Errorf("empty predicate in a cardinality declaration")
+	}
+	if maxValues < 0 {
+		return fmt.Errorf("invalid cardinality %d for predicate %q", maxValues, predicate)
+	}
+	switch onConflict {
+	case "":
+		onConflict = ConflictReject
+	case ConflictReject, ConflictReplace, ConflictFlag:
+	default:
+		return fmt.Errorf("unknown conflict policy %q for predicate %q", onConflict, predicate)
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	declaration := kg.declaration(predicate)
+	declaration.MaxValues = maxValues
+	declaration.OnConflict = onConflict
+	if maxValues == 0 {
+		declaration.OnConflict = ""
+		if *declaration == (PredicateDeclaration{Name: declaration.Name}) {
+			delete(kg.declarations, foldKey(predicate))
+		}
+	}
+	return nil
+}
+
+// DeclareFunctional declares that a subject may have only one value for the predicate, as for born_in,
+// and what happens when a triple gives it another one.
+func (kg *KG) DeclareFunctional(predicate string, onConflict ConflictPolicy) error {
+	return kg.DeclareCardinality(predicate, 1, onConflict)
+}
+
+// CardinalityError is returned when a triple would give a subject more values for a predicate
+// than its declared cardinality allows, and the conflict policy rejects it.
+type CardinalityError struct {
+	Subject   string
+	Predicate string
+	Value     string   // Value of the rejected triple
+	Values    []string // Values the subject already has
+	MaxValues int
+}
+
+// Error describes the values in conflict.
+func (err *CardinalityError) Error() string {
+	return fmt.Sprintf("%q already has %d value(s) for %s, which allows %d: %s; cannot add %s",
+		err.Subject, len(err.Values), err.Predicate, err.MaxValues, strings.Join(err.Values, ", "), err.Value)
+}
+
+// Contradiction describes a subject having more values for a predicate than its declared cardinality allows.
+type Contradiction struct {
+	Subject   *Node
+	Predicate string       // Name of the declared predicate
+	MaxValues int          // Declared cardinality of the predicate
+	Values    []string     // Distinct values of the subject holding at the same time, in the order they were stated
+	Triples   []*Predicate // Triples stating the values of the subject, in all graphs
+}
+
+// String describes the contradiction, as in "Alice has 2 values for born_in, which allows 1: Paris, Lyon".
+func (contradiction Contradiction) String() string {
+	return fmt.Sprintf("%s has %d values for %s, which allows %d: %s", contradiction.Subject.Lexical,
+		len(contradiction.Values), contradiction.Predicate, contradiction.MaxValues, strings.Join(contradiction.Values, ", "))
+}
+
+// FindContradictions returns the subjects having more values for a predicate than its declared cardinality allows
+// at the same time, such as two birth places for a functional born_in, sorted by subject label and predicate.
+// Values whose validity periods do not overlap, such as successive employers, do not contradict each other.
+// Only asserted triples are considered. If subject is not empty, only the entities matching it are reported.
+// The caseSensitiveSearch parameter determines if the subject matching is case-sensitive.
+func (kg *KG) FindContradictions(subject string, caseSensitiveSearch bool) []Contradiction {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	var result []Contradiction
+	for _, declaration := range kg.sortedDeclarations() {
+		if declaration.MaxValues == 0 {
+			continue
+		}
+
+		// Group the triples by subject
+		bySubject := make(map[int64][]*Predicate)
+		for _, pred := range kg.lookupPredicates(declaration.Name, false) {
+			if !pred.Inferred() && (subject == "" || pred.F.(*Node).matches(subject, caseSensitiveSearch)) {
+				bySubject[pred.F.ID()] = append(bySubject[pred.F.ID()], pred)
+			}
+		}
+
+		for _, triples := range bySubject {
+			sortByCreation(triples)
+			values := concurrentValues(triples)
+			if len(values) <= declaration.MaxValues {
+				continue
+			}
+			labels := make([]string, len(values))
+			for i, value := range values {
+				labels[i] = value.String()
+			}
+			result = append(result, Contradiction{
+				Subject:   triples[0].F.(*Node),
+				Predicate: declaration.Name,
+				MaxValues: declaration.MaxValues,
+				Values:    labels,
+				Triples:   triples,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Subject.Lexical != result[j].Subject.Lexical {
+			return result[i].Subject.Lexical < result[j].Subject.Lexical
+		}
+		if result[i].Predicate != result[j].Predicate {
+			return result[i].Predicate < result[j].Predicate
+		}
+		return result[i].Subject.ID() < result[j].Subject.ID()
+	})
+	return result
+}
+
+// checkCardinality checks that giving the value objectNode to subjectNode for the predicate,
+// during the validity period recorded in validity, respects the declared cardinality of the predicate.
+// Only the values holding at some moment of that period count.
+// It returns the triples to remove after inserting the new one under the replace policy,
+// and a *CardinalityError under the reject policy.
+// The caller must hold at least a read lock.
+func (kg *KG) checkCardinality(subjectNode, objectNode *Node, predicate string, validity Metadata) ([]*Predicate, error) {
+	declaration := kg.declarations[foldKey(predicate)]
+	if declaration == nil || declaration.MaxValues == 0 {
+		return nil, nil
+	}
+
+	var triples []*Predicate
+	for _, lines := range kg.from[subjectNode.ID()] {
+		for _, pred := range lines {
+			if !pred.Inferred() && predicateMatches(pred.Subject, predicate, false) && pred.Metadata.overlaps(validity) {
+				triples = append(triples, pred)
+			}
+		}
+	}
+	sortByCreation(triples)
+	values := distinctValues(triples)
+
+	// A value the subject already has, maybe in another graph, is not a new value
+	for _, value := range values {
+		if valueKey(value) == valueKey(objectNode) {
+			return nil, nil
+		}
+	}
+	if len(values) < declaration.MaxValues {
+		return nil, nil
+	}
+
+	switch declaration.OnConflict {
+	case ConflictFlag:
+		return nil, nil
+	case ConflictReplace:
+		// Keep the most recent values, leaving room for the new one
+		stale := make(map[string]bool)
+		for _, value := range values[:len(values)-declaration.MaxValues+1] {
+			stale[valueKey(value)] = true
+		}
+		var replaced []*Predicate
+		for _, pred := range triples {
+			if stale[valueKey(pred.T.(*Node))] {
+				replaced = append(replaced, pred)
+			}
+		}
+		return replaced, nil
+	default:
+		labels := make([]string, len(values))
+		for i, value := range values {
+			labels[i] = value.String()
+		}
+		return nil, &CardinalityError{
+			Subject:   subjectNode.Lexical,
+			Predicate: declaration.Name,
+			Value:     objectNode.String(),
+			Values:    labels,
+			MaxValues: declaration.MaxValues,
+		}
+	}
+}
+
+// concurrentValues returns the largest set of distinct objects of the triples that hold at the same time,
+// in the order of their first triple.
+func concurrentValues(triples []*Predicate) []*Node {
+	var result []*Node
+	// The largest set holds at the start of the validity of one of the triples
+	for _, start := range triples {
+		var holding []*Predicate
+		for _, pred := range triples {
+			if holdsAtStartOf(pred.Metadata, start.Metadata) {
+				holding = append(holding, pred)
+			}
+		}
+		if values := distinctValues(holding); len(values) > len(result) {
+			result = values
+		}
+	}
+	return result
+}
+
+// holdsAtStartOf reports whether a fact holds when the validity of another fact starts,
+// a zero start being the beginning of time.
+func holdsAtStartOf(metadata, other Metadata) bool {
+	if other.ValidFrom.IsZero() {
+		return metadata.ValidFrom.IsZero()
+	}
+	return metadata.ValidAt(other.ValidFrom)
+}
+
+// distinctValues returns the distinct objects of the triples, in the order of their first triple.
+func distinctValues(triples []*Predicate) []*Node {
+	seen := make(map[string]bool)
+	var values []*Node
+	for _, pred := range triples {
+		value := pred.T.(*Node)
+		if !seen[valueKey(value)] {
+			seen[valueKey(value)] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// sortByCreation sorts the predicates by creation time, then by ID.
+func sortByCreation(predicates []*Predicate) {
+	sort.Slice(predicates, func(i, j int) bool {
+		if !predicates[i].Metadata.Created.Equal(predicates[j].Metadata.Created) {
+			return predicates[i].Metadata.Created.Before(predicates[j].Metadata.Created)
+		}
+		return predicates[i].ID() < predicates[j].ID()
+	})
+}
@@ -0,0 +1,66 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardinality(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.DeclareFunctional("born_in", ""))
+	assert.NoError(kg.DeclareFunctional("first_released", ConflictReplace))
+	assert.NoError(kg.DeclareCardinality("nationality", 2, ConflictFlag))
+	assert.Error(kg.DeclareCardinality("nationality", 2, "ignore"))
+	assert.Error(kg.DeclareCardinality("nationality", -1, ConflictFlag))
+	assert.True(kg.Declaration("born_in").Functional())
+	assert.Equal(ConflictReject, kg.Declaration("born_in").OnConflict)
+
+	// Reject
+	assert.NoError(kg.InsertTriple("Alice", "born_in", "Paris", true))
+	assert.NoError(kg.InsertTriple("Alice", "born_in", "Paris", true, "census"), "The same value in another graph is not a conflict")
+	var cardinalityErr *CardinalityError
+	assert.ErrorAs(kg.InsertTriple("Alice", "born_in", "Lyon", true), &cardinalityErr)
+	assert.Equal([]string{"Paris"}, cardinalityErr.Values)
+	assert.Nil(kg.FindNode("Lyon", true), "The rejected object is not left behind")
+	assert.Error(kg.InsertTriple("Alice", "Born_In", "Lyon", true), "Declarations are case-insensitive")
+
+	// Replace
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1990", ObjectType: DatatypeInteger}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, true))
+	assert.Equal([][3]string{{"Python", "first_released", "1991"}}, kg.FindTriples("Python", "first_released", "", true))
+	assert.Len(kg.lookupLiterals("1990", true), 0, "The replaced literal is removed")
+
+	// Flag
+	assert.NoError(kg.InsertTriple("Carol", "nationality", "French", true))
+	assert.NoError(kg.InsertTriple("Carol", "nationality", "German", true))
+	assert.Empty(kg.FindContradictions("", true))
+	assert.NoError(kg.InsertTriple("Carol", "nationality", "Swiss", true))
+	assert.Len(kg.FindTriples("Carol", "nationality", "", true), 3)
+
+	// Conflicts stored before the declaration are reported as well
+	assert.NoError(kg.InsertTriple("Dave", "died_in", "Rome", true))
+	assert.NoError(kg.InsertTriple("Dave", "died_in", "Milan", true))
+	assert.NoError(kg.DeclareFunctional("died_in", ConflictReject))
+
+	contradictions := kg.FindContradictions("", true)
+	assert.Len(contradictions, 2)
+	assert.Equal("Carol", contradictions[0].Subject.Lexical)
+	assert.Equal("nationality", contradictions[0].Predicate)
+	assert.Equal([]string{"French", "German", "Swiss"}, contradictions[0].Values)
+	assert.Len(contradictions[0].Triples, 3)
+	assert.Equal("Dave", contradictions[1].Subject.Lexical)
+	assert.Equal([]string{"Rome", "Milan"}, contradictions[1].Values)
+	assert.Len(kg.FindContradictions("dave", false), 1)
+	assert.Empty(kg.FindContradictions("Alice", true))
+
+	// Removing the cardinality keeps the other declarations
+	assert.NoError(kg.DeclareInverse("born_in", "birthplace_of"))
+	assert.NoError(kg.DeclareCardinality("born_in", 0, ""))
+	assert.Equal(&PredicateDeclaration{Name: "born_in", Inverse: "birthplace_of"}, kg.Declaration("born_in"))
+	assert.NoError(kg.InsertTriple("Alice", "born_in", "Lyon", true))
+	assert.NoError(kg.DeclareCardinality("nationality", 0, ""))
+	assert.Nil(kg.Declaration("nationality"))
+}
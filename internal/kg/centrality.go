@@ -0,0 +1,175 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/network"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Measure is a centrality measure by which entities are ranked.
+type Measure string
+
+const (
+	MeasurePageRank    Measure = "pagerank"    // Importance an entity receives from the entities whose triples point to it
+	MeasureDegree      Measure = "degree"      // Number of triples linking the entity to entities, in either direction
+	MeasureInDegree    Measure = "in_degree"   // Number of triples whose object is the entity
+	MeasureOutDegree   Measure = "out_degree"  // Number of triples whose subject is the entity
+	MeasureBetweenness Measure = "betweenness" // Number of shortest paths between other entities going through the entity
+)
+
+// pageRankDamping and pageRankTolerance are the damping factor and the convergence threshold of PageRank.
+const (
+	pageRankDamping   = 0.85
+	pageRankTolerance = 1e-8
+)
+
+// CentralityOptions restricts the triples taken into account to measure the centrality of entities.
+type CentralityOptions struct {
+	Predicates []string // Predicates of the triples taken into account, all predicates if empty
+	Graphs     []string // Names of the graphs whose triples are taken into account, all graphs if empty
+}
+
+// Centrality gives the measures of how central an entity is in the knowledge graph.
+// Only the triples linking entities are taken into account: literal values do not make an entity central.
+type Centrality struct {
+	Entity      *Node
+	PageRank    float64 // Share of the PageRank of the graph, the PageRanks of all entities summing to 1
+	InDegree    int     // Number of triples whose object is the entity
+	OutDegree   int     // Number of triples whose subject is the entity
+	Betweenness float64 // Number of shortest paths between pairs of other entities going through the entity, ignoring the direction of triples
+}
+
+// Degree returns the number of triples linking the entity to entities, whatever their direction.
+func (c Centrality) Degree() int {
+	return c.InDegree + c.OutDegree
+}
+
+// Score returns the value of the given measure for the entity.
+func (c Centrality) Score(measure Measure) float64 {
+	switch measure {
+	case MeasurePageRank:
+		return c.PageRank
+	case MeasureInDegree:
+		return float64(c.InDegree)
+	case MeasureOutDegree:
+		return float64(c.OutDegree)
+	case MeasureBetweenness:
+		return c.Betweenness
+	default:
+		return float64(c.Degree())
+	}
+}
+
+// Centralities returns every centrality measure for each entity of the knowledge graph, sorted by label.
+// Several triples linking the same entities count once for PageRank and betweenness, but each counts for the degrees.
+// The caseSensitiveSearch parameter determines if the predicates of the options are compared case-sensitively.
+func (kg *KG) Centralities(options CentralityOptions, caseSensitiveSearch bool) []Centrality {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	centralities := kg.centralities(options, true, true, caseSensitiveSearch)
+	sort.Slice(centralities, func(i, j int) bool {
+		return compareNodes(centralities[i].Entity, centralities[j].Entity) < 0
+	})
+	return centralities
+}
+
+// RankEntities returns the n entities with the highest value of the measure, highest first,
+// ties being ordered by label; all the entities are returned if n is zero.
+// The degrees are always given, but PageRank and betweenness are only computed to rank by them,
+// and left zero otherwise.
+// It returns an error if the measure is unknown or if n is negative.
+// The caseSensitiveSearch parameter determines if the predicates of the options are compared case-sensitively.
+func (kg *KG) RankEntities(measure Measure, n int, options CentralityOptions, caseSensitiveSearch bool) ([]Centrality, error) {
+	switch measure {
+	case MeasurePageRank, MeasureDegree, MeasureInDegree, MeasureOutDegree, MeasureBetweenness:
+	default:
+		return nil, fmt.Errorf("unknown measure %q: expected pagerank, degree, in_degree, out_degree or betweenness", measure)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of entities %d: it cannot be negative", n)
+	}
+
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	centralities := kg.centralities(options, measure == MeasurePageRank, measure == MeasureBetweenness, caseSensitiveSearch)
+	sort.Slice(centralities, func(i, j int) bool {
+		a, b := centralities[i].Score(measure), centralities[j].Score(measure)
+		if a != b {
+			return a > b
+		}
+		return compareNodes(centralities[i].Entity, centralities[j].Entity) < 0
+	})
+	if n > 0 && n < len(centralities) {
+		centralities = centralities[:n]
+	}
+	return centralities, nil
+}
+
+// centralities measures the centrality of each entity, in the order of their creation.
+// PageRank and betweenness, much more expensive than the degrees, are only computed if withPageRank
+// and withBetweenness are set.
+// The caller must hold at least a read lock.
+func (kg *KG) centralities(options CentralityOptions, withPageRank, withBetweenness bool, caseSensitiveSearch bool) []Centrality {
+	entities := kg.entities()
+	if len(entities) == 0 {
+		return nil
+	}
+	selected := predicateSelector(options.Predicates, options.Graphs, caseSensitiveSearch)
+
+	centralities := make([]Centrality, len(entities))
+	index := make(map[int64]int, len(entities))
+	for i, entity := range entities {
+		centralities[i].Entity = entity
+		index[entity.ID()] = i
+	}
+	for _, pred := range kg.allPredicates() {
+		subject, isSubjectEntity := index[pred.F.ID()]
+		object, isObjectEntity := index[pred.T.ID()]
+		if pred.Subject == "" || !isSubjectEntity || !isObjectEntity || !selected(pred) {
+			continue
+		}
+		centralities[subject].OutDegree++
+		centralities[object].InDegree++
+	}
+
+	if !withPageRank && !withBetweenness {
+		return centralities
+	}
+	g := kg.entityGraph(entities, selected)
+	if withPageRank {
+		// The sparse implementation keeps the memory linear in the number of triples
+		for id, rank := range network.PageRankSparse(g, pageRankDamping, pageRankTolerance) {
+			centralities[index[id]].PageRank = rank
+		}
+	}
+	if withBetweenness {
+		// Each pair of entities is counted in both directions in an undirected graph
+		for id, betweenness := range network.Betweenness(graph.Undirect{G: g}) {
+			centralities[index[id]].Betweenness = betweenness / 2
+		}
+	}
+	return centralities
+}
+
+// entityGraph returns a directed graph of the entities, with an edge from an entity to another
+// wherever a selected triple links them. Triples with a literal object and self-loops are left out.
+// The caller must hold at least a read lock.
+func (kg *KG) entityGraph(entities []*Node, selected func(*Predicate) bool) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for _, entity := range entities {
+		g.AddNode(simple.Node(entity.ID()))
+	}
+	for _, pred := range kg.allPredicates() {
+		subject, object := pred.F.ID(), pred.T.ID()
+		if pred.Subject == "" || subject == object || g.Node(subject) == nil || g.Node(object) == nil || !selected(pred) {
+			continue
+		}
+		g.SetEdge(simple.Edge{F: simple.Node(subject), T: simple.Node(object)})
+	}
+	return g
+}
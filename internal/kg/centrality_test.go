@@ -0,0 +1,83 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCentrality(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	for _, triple := range [][3]string{
+		{"Alice", "knows", "Bob"},
+		{"Alice", "knows", "Carol"},
+		{"Alice", "knows", "Dave"},
+		{"Bob", "knows", "Carol"},
+		{"Dave", "works_for", "Acme"},
+	} {
+		assert.NoError(kg.InsertTriple(triple[0], triple[1], triple[2], true))
+	}
+	assert.NoError(kg.Insert(Triple{Subject: "Acme", Predicate: "founded", Object: "1990", ObjectType: DatatypeInteger}, true))
+	assert.NoError(kg.InsertTriple("Bob", "knows", "Bob", true))
+
+	labels := func(centralities []Centrality) []string {
+		var result []string
+		for _, centrality := range centralities {
+			result = append(result, centrality.Entity.Lexical)
+		}
+		return result
+	}
+
+	// Every measure, by label; literal values are not counted and self-loops only count for the degrees
+	centralities := kg.Centralities(CentralityOptions{}, true)
+	assert.Equal([]string{"Acme", "Alice", "Bob", "Carol", "Dave"}, labels(centralities))
+	var total float64
+	for _, centrality := range centralities {
+		total += centrality.PageRank
+	}
+	assert.InDelta(1, total, 1e-6)
+	alice, bob, dave := centralities[1], centralities[2], centralities[4]
+	assert.Equal(0, alice.InDegree)
+	assert.Equal(3, alice.OutDegree)
+	assert.Equal(4, bob.Degree())
+	assert.InDelta(4, alice.Betweenness, 1e-9)
+	assert.InDelta(3, dave.Betweenness, 1e-9)
+	assert.Zero(bob.Betweenness)
+	assert.Greater(centralities[3].PageRank, alice.PageRank)
+
+	// Rankings
+	ranking, err := kg.RankEntities(MeasureDegree, 2, CentralityOptions{}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Bob", "Alice"}, labels(ranking))
+	assert.Zero(ranking[0].PageRank)
+	ranking, err = kg.RankEntities(MeasureBetweenness, 0, CentralityOptions{}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Alice", "Dave", "Acme", "Bob", "Carol"}, labels(ranking))
+	ranking, err = kg.RankEntities(MeasureInDegree, 1, CentralityOptions{}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Bob"}, labels(ranking))
+
+	// Restricted to some predicates, ties being ordered by label
+	ranking, err = kg.RankEntities(MeasureDegree, 3, CentralityOptions{Predicates: []string{"WORKS_FOR"}}, false)
+	assert.NoError(err)
+	assert.Equal([]string{"Acme", "Dave", "Alice"}, labels(ranking))
+	assert.Zero(ranking[2].Degree())
+	ranking, err = kg.RankEntities(MeasurePageRank, 1, CentralityOptions{Predicates: []string{"works_for"}}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Acme"}, labels(ranking))
+
+	ranking, err = kg.RankEntities(MeasureDegree, 3, CentralityOptions{Graphs: []string{"draft"}}, true)
+	assert.NoError(err)
+	assert.Zero(ranking[0].Degree())
+
+	_, err = kg.RankEntities("fame", 3, CentralityOptions{}, true)
+	assert.Error(err)
+	_, err = kg.RankEntities(MeasureDegree, -1, CentralityOptions{}, true)
+	assert.Error(err)
+
+	ranking, err = NewKG("empty").RankEntities(MeasurePageRank, 3, CentralityOptions{}, true)
+	assert.NoError(err)
+	assert.Empty(ranking)
+}
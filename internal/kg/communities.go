@@ -0,0 +1,181 @@
+package kg
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"gonum.org/v1/gonum/graph/community"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// communitySeed seeds the random order in which the Louvain method visits the entities,
+// so that the same graph is always split into the same communities.
+const communitySeed = 1
+
+// CommunitySource is the source of the triples recording the community of the entities, written by AssignCommunities.
+const CommunitySource = "detect_communities"
+
+// CommunityOptions restricts the triples taken into account to detect communities and sets their granularity.
+type CommunityOptions struct {
+	Predicates []string // Predicates of the triples taken into account, all predicates if empty
+	Graphs     []string // Names of the graphs whose triples are taken into account, all graphs if empty
+	Resolution float64  // Higher values give more and smaller communities, 1 if zero
+}
+
+// Community is a group of entities more densely linked to each other than to the rest of the graph.
+type Community struct {
+	Name    string  // Label of the most representative member
+	Members []*Node // Members, the most representative first: those with the most triples to other members, then by label
+}
+
+// Representatives returns the n most representative members of the community, all of them if it has fewer.
+func (c Community) Representatives(n int) []*Node {
+	return c.Members[:min(n, len(c.Members))]
+}
+
+// Clustering is a partition of the entities of the knowledge graph into communities.
+type Clustering struct {
+	Communities []Community // Communities, the largest first, then by name; entities linked to no other entity form a community of their own
+	Modularity  float64     // How much denser the links within the communities are than expected at random, between -1/2 and 1
+}
+
+// Communities partitions the entities of the knowledge graph into communities with the Louvain method,
+// which maximizes the modularity of the partition. The direction of the triples is ignored,
+// and the entities linked by several triples are the more likely to belong to the same community.
+// Only the triples between entities are taken into account; the same graph always gets the same communities.
+// It returns an error if the resolution is negative.
+// The caseSensitiveSearch parameter determines if the predicates of the options are compared case-sensitively.
+func (kg *KG) Communities(options CommunityOptions, caseSensitiveSearch bool) (*Clustering, error) {
+	resolution := options.Resolution
+	if resolution < 0 {
+		return nil, fmt.Errorf("invalid resolution %g: it cannot be negative", resolution)
+	}
+	if resolution == 0 {
+		resolution = 1
+	}
+
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	entities := kg.entities()
+	if len(entities) == 0 {
+		return &Clustering{}, nil
+	}
+	nodes := make(map[int64]*Node, len(entities))
+	for _, entity := range entities {
+		nodes[entity.ID()] = entity
+	}
+
+	// Weigh the link between two entities by the number of triples between them
+	selected := predicateSelector(options.Predicates, options.Graphs, caseSensitiveSearch)
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, entity := range entities {
+		g.AddNode(simple.Node(entity.ID()))
+	}
+	for _, pred := range kg.allPredicates() {
+		subject, object := pred.F.ID(), pred.T.ID()
+		if pred.Subject == "" || subject == object || nodes[subject] == nil || nodes[object] == nil || !selected(pred) {
+			continue
+		}
+		weight := 1.0
+		if edge := g.WeightedEdge(subject, object); edge != nil {
+			weight += edge.Weight()
+		}
+		g.SetWeightedEdge(g.NewWeightedEdge(simple.Node(subject), simple.Node(object), weight))
+	}
+
+	groups := community.Modularize(g, resolution, rand.NewPCG(communitySeed, communitySeed)).Communities()
+	clustering := &Clustering{Modularity: community.Q(g, groups, resolution)}
+	if math.IsNaN(clustering.Modularity) {
+		// Without any triple, no partition is better than another
+		clustering.Modularity = 0
+	}
+
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		// Rank the members by the weight of their links within the community
+		inside := make(map[int64]bool, len(group))
+		for _, member := range group {
+			inside[member.ID()] = true
+		}
+		links := make(map[int64]float64, len(group))
+		members := make([]*Node, 0, len(group))
+		for _, member := range group {
+			neighbors := g.From(member.ID())
+			for neighbors.Next() {
+				if neighbor := neighbors.Node().ID(); inside[neighbor] {
+					links[member.ID()] += g.WeightedEdge(member.ID(), neighbor).Weight()
+				}
+			}
+			members = append(members, nodes[member.ID()])
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if a, b := links[members[i].ID()], links[members[j].ID()]; a != b {
+				return a > b
+			}
+			return compareNodes(members[i], members[j]) < 0
+		})
+		clustering.Communities = append(clustering.Communities, Community{Name: members[0].Lexical, Members: members})
+	}
+	sort.Slice(clustering.Communities, func(i, j int) bool {
+		a, b := clustering.Communities[i], clustering.Communities[j]
+		if len(a.Members) != len(b.Members) {
+			return len(a.Members) > len(b.Members)
+		}
+		return compareNodes(a.Members[0], b.Members[0]) < 0
+	})
+	return clustering, nil
+}
+
+// AssignCommunities records the community of each entity of the clustering as a triple of the graph,
+// whose predicate is the given one and whose object is the name of the community as a string literal,
+// such as (Bob, community, "Alice"). Entities forming a community of their own get no triple.
+// The triples are marked with CommunitySource as their source. The triples with that predicate and source
+// already in the graph are removed first, so that a new clustering replaces the previous one,
+// while the triples with that predicate inserted otherwise are kept.
+// It returns the number of triples inserted, and an error if the predicate is empty or an insertion fails.
+func (kg *KG) AssignCommunities(clustering *Clustering, predicate string, graph string) (int, error) {
+	if predicate == "" {
+		return 0, fmt.Errorf("the community predicate cannot be empty")
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	// Forget the previous clustering
+	var previous []*Predicate
+	for _, pred := range kg.allPredicates() {
+		if pred.Graph == graph && pred.Subject == predicate && pred.Metadata.Source == CommunitySource {
+			previous = append(previous, pred)
+		}
+	}
+	kg.removeTriples(previous)
+
+	inserted := 0
+	for _, c := range clustering.Communities {
+		if len(c.Members) < 2 {
+			continue
+		}
+		literal := Literal{Value: c.Name, Datatype: DatatypeString}
+		for _, member := range c.Members {
+			err := kg.insert(Triple{
+				Subject:    member.Lexical,
+				SubjectKey: member.Key,
+				Predicate:  predicate,
+				Object:     c.Name,
+				ObjectType: DatatypeString,
+				Graph:      graph,
+				Source:     CommunitySource,
+			}, &literal, true)
+			if err != nil {
+				return inserted, fmt.Errorf("cannot assign %s to the community of %s: %w", member.Lexical, c.Name, err)
+			}
+			inserted++
+		}
+	}
+	return inserted, nil
+}
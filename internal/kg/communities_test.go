@@ -0,0 +1,93 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommunities(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	// Two groups of friends linked by Carol and Dave
+	for _, triple := range [][3]string{
+		{"Alice", "knows", "Bob"},
+		{"Alice", "knows", "Carol"},
+		{"Bob", "knows", "Carol"},
+		{"Bob", "likes", "Carol"},
+		{"Dave", "knows", "Eve"},
+		{"Dave", "knows", "Frank"},
+		{"Eve", "knows", "Frank"},
+		{"Carol", "knows", "Dave"},
+	} {
+		assert.NoError(kg.InsertTriple(triple[0], triple[1], triple[2], true))
+	}
+	assert.NoError(kg.Insert(Triple{Subject: "Zed", Predicate: "age", Object: "3", ObjectType: DatatypeInteger}, true))
+
+	labels := func(nodes []*Node) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Lexical)
+		}
+		return result
+	}
+
+	clustering, err := kg.Communities(CommunityOptions{}, true)
+	assert.NoError(err)
+	assert.Len(clustering.Communities, 3)
+	assert.Equal("Bob", clustering.Communities[0].Name)
+	assert.Equal([]string{"Bob", "Carol", "Alice"}, labels(clustering.Communities[0].Members))
+	assert.Equal("Dave", clustering.Communities[1].Name)
+	assert.Equal([]string{"Dave", "Eve", "Frank"}, labels(clustering.Communities[1].Members))
+	assert.Equal([]string{"Zed"}, labels(clustering.Communities[2].Members))
+	assert.Equal([]string{"Bob", "Carol"}, labels(clustering.Communities[0].Representatives(2)))
+	assert.Len(clustering.Communities[2].Representatives(3), 1)
+	assert.Greater(clustering.Modularity, 0.3)
+
+	// The same graph always gets the same communities
+	again, err := kg.Communities(CommunityOptions{}, true)
+	assert.NoError(err)
+	assert.Equal(clustering, again)
+
+	// Without the bridge, restricted to some predicates, or at a higher resolution
+	clustering, err = kg.Communities(CommunityOptions{Predicates: []string{"LIKES"}}, false)
+	assert.NoError(err)
+	assert.Equal([]string{"Bob", "Carol"}, labels(clustering.Communities[0].Members))
+	assert.Len(clustering.Communities, 6)
+	clustering, err = kg.Communities(CommunityOptions{Resolution: 10}, true)
+	assert.NoError(err)
+	assert.Greater(len(clustering.Communities), 3)
+	clustering, err = kg.Communities(CommunityOptions{Graphs: []string{"draft"}}, true)
+	assert.NoError(err)
+	assert.Len(clustering.Communities, 7)
+	assert.Zero(clustering.Modularity)
+
+	_, err = kg.Communities(CommunityOptions{Resolution: -1}, true)
+	assert.Error(err)
+	clustering, err = NewKG("empty").Communities(CommunityOptions{}, true)
+	assert.NoError(err)
+	assert.Empty(clustering.Communities)
+
+	// Memberships written back replace the previous ones
+	clustering, err = kg.Communities(CommunityOptions{}, true)
+	assert.NoError(err)
+	inserted, err := kg.AssignCommunities(clustering, "community", DefaultGraph)
+	assert.NoError(err)
+	assert.Equal(6, inserted)
+	assert.Equal([][3]string{{"Eve", "community", "Dave"}}, kg.FindTriples("Eve", "community", "", true))
+	assert.Empty(kg.FindTriples("Zed", "community", "", true))
+
+	clustering, err = kg.Communities(CommunityOptions{Predicates: []string{"likes"}}, true)
+	assert.NoError(err)
+	assert.NoError(kg.Insert(Triple{Subject: "Zed", Predicate: "community", Object: "Go users", ObjectType: DatatypeString}, true))
+	inserted, err = kg.AssignCommunities(clustering, "community", DefaultGraph)
+	assert.NoError(err)
+	assert.Equal(2, inserted)
+	assert.Empty(kg.FindTriples("Eve", "community", "", true))
+	assert.Equal([][3]string{{"Zed", "community", "Go users"}}, kg.FindTriples("Zed", "community", "", true), "Only the memberships written before are replaced")
+	assert.Len(kg.FindTriples("", "community", "", true), 3)
+
+	_, err = kg.AssignCommunities(clustering, "", DefaultGraph)
+	assert.Error(err)
+}
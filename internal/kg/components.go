@@ -0,0 +1,138 @@
+package kg
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// ComponentOptions restricts the triples taken into account to split the knowledge graph into components.
+type ComponentOptions struct {
+	Predicates []string // Predicates of the triples taken into account, all predicates if empty
+	Graphs     []string // Names of the graphs whose triples are taken into account, all graphs if empty
+}
+
+// HealthReport describes how the entities of the knowledge graph hold together.
+// Components are sorted from the largest to the smallest, then by label, and their entities by label.
+type HealthReport struct {
+	Entities         int          // Number of entities
+	Triples          int          // Number of triples between entities, self-loops included
+	WeakComponents   [][]*Node    // Groups of entities linked by triples whatever their direction
+	StrongComponents [][]*Node    // Groups of entities reachable from each other following the direction of triples
+	Isolated         []*Node      // Entities linked to no other entity, though they may have literal values
+	Orphans          []*Node      // Entities that are neither the subject nor the object of any triple, whatever the options
+	SelfLoops        []*Predicate // Triples whose subject and object are the same entity, sorted by subject, predicate and object
+}
+
+// WeaklyConnectedComponents returns the groups of entities linked to each other by triples,
+// whatever the direction of the triples: the islands of the knowledge graph.
+// An entity linked to no other entity forms a component of its own.
+// The components are sorted from the largest to the smallest, then by label, and their entities by label.
+// The caseSensitiveSearch parameter determines if the predicates of the options are compared case-sensitively.
+func (kg *KG) WeaklyConnectedComponents(options ComponentOptions, caseSensitiveSearch bool) [][]*Node {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	g := kg.entityGraph(kg.entities(), predicateSelector(options.Predicates, options.Graphs, caseSensitiveSearch))
+	return kg.components(topo.ConnectedComponents(graph.Undirect{G: g}))
+}
+
+// StronglyConnectedComponents returns the groups of entities such that each entity of a group can be reached
+// from the others by following triples from their subject to their object.
+// An entity on no cycle forms a component of its own.
+// The components are sorted from the largest to the smallest, then by label, and their entities by label.
+// The caseSensitiveSearch parameter determines if the predicates of the options are compared case-sensitively.
+func (kg *KG) StronglyConnectedComponents(options ComponentOptions, caseSensitiveSearch bool) [][]*Node {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	g := kg.entityGraph(kg.entities(), predicateSelector(options.Predicates, options.Graphs, caseSensitiveSearch))
+	return kg.components(topo.TarjanSCC(g))
+}
+
+// Orphans returns the entities that are neither the subject nor the object of any triple, sorted by label.
+func (kg *KG) Orphans() []*Node {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return kg.orphans()
+}
+
+// Health reports the components of the knowledge graph, the entities linked to no other entity,
+// the orphan entities and the self-loops, to tell whether the graph has broken into disconnected islands.
+// The caseSensitiveSearch parameter determines if the predicates of the options are compared case-sensitively.
+func (kg *KG) Health(options ComponentOptions, caseSensitiveSearch bool) *HealthReport {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	entities := kg.entities()
+	selected := predicateSelector(options.Predicates, options.Graphs, caseSensitiveSearch)
+	g := kg.entityGraph(entities, selected)
+	report := &HealthReport{
+		Entities:         len(entities),
+		WeakComponents:   kg.components(topo.ConnectedComponents(graph.Undirect{G: g})),
+		StrongComponents: kg.components(topo.TarjanSCC(g)),
+		Orphans:          kg.orphans(),
+	}
+
+	for _, component := range report.WeakComponents {
+		if len(component) == 1 {
+			report.Isolated = append(report.Isolated, component[0])
+		}
+	}
+	sort.Slice(report.Isolated, func(i, j int) bool {
+		return compareNodes(report.Isolated[i], report.Isolated[j]) < 0
+	})
+
+	for _, pred := range kg.allPredicates() {
+		if pred.Subject == "" || pred.T.(*Node).IsLiteral() || !selected(pred) {
+			continue
+		}
+		report.Triples++
+		if pred.F.ID() == pred.T.ID() {
+			report.SelfLoops = append(report.SelfLoops, pred)
+		}
+	}
+	sortTriples(report.SelfLoops, QueryOptions{})
+	return report
+}
+
+// components returns the entities of the components, sorted from the largest component to the smallest,
+// then by label, and their entities by label.
+// The caller must hold at least a read lock.
+func (kg *KG) components(groups [][]graph.Node) [][]*Node {
+	components := make([][]*Node, 0, len(groups))
+	for _, group := range groups {
+		component := make([]*Node, 0, len(group))
+		for _, member := range group {
+			component = append(component, kg.nodes[member.ID()])
+		}
+		sort.Slice(component, func(i, j int) bool {
+			return compareNodes(component[i], component[j]) < 0
+		})
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if len(components[i]) != len(components[j]) {
+			return len(components[i]) > len(components[j])
+		}
+		return compareNodes(components[i][0], components[j][0]) < 0
+	})
+	return components
+}
+
+// orphans returns the entities without any triple, sorted by label.
+// The caller must hold at least a read lock.
+func (kg *KG) orphans() []*Node {
+	var orphans []*Node
+	for _, entity := range kg.entities() {
+		if kg.isOrphan(entity) {
+			orphans = append(orphans, entity)
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return compareNodes(orphans[i], orphans[j]) < 0
+	})
+	return orphans
+}
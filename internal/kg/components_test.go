@@ -0,0 +1,93 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponents(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	for _, triple := range [][3]string{
+		{"Alice", "knows", "Bob"},
+		{"Bob", "knows", "Carol"},
+		{"Carol", "knows", "Alice"},
+		{"Carol", "works_for", "Acme"},
+		{"Dave", "knows", "Eve"},
+		{"Zed", "knows", "Zed"},
+	} {
+		assert.NoError(kg.InsertTriple(triple[0], triple[1], triple[2], true))
+	}
+	assert.NoError(kg.Insert(Triple{Subject: "Yann", Predicate: "age", Object: "3", ObjectType: DatatypeInteger}, true))
+	// Removing the only triple of an entity leaves it behind as an orphan
+	assert.NoError(kg.InsertTriple("Xavier", "knows", "Alice", true))
+	removed, err := kg.RemoveTriple("Xavier", "knows", "Alice", true)
+	assert.NoError(err)
+	assert.True(removed)
+
+	components := func(groups [][]*Node) [][]string {
+		var result [][]string
+		for _, group := range groups {
+			var labels []string
+			for _, node := range group {
+				labels = append(labels, node.Lexical)
+			}
+			result = append(result, labels)
+		}
+		return result
+	}
+	labels := func(nodes []*Node) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Lexical)
+		}
+		return result
+	}
+
+	assert.Equal([][]string{
+		{"Acme", "Alice", "Bob", "Carol"},
+		{"Dave", "Eve"},
+		{"Xavier"},
+		{"Yann"},
+		{"Zed"},
+	}, components(kg.WeaklyConnectedComponents(ComponentOptions{}, true)))
+	assert.Equal([][]string{
+		{"Alice", "Bob", "Carol"},
+		{"Acme"},
+		{"Dave"},
+		{"Eve"},
+		{"Xavier"},
+		{"Yann"},
+		{"Zed"},
+	}, components(kg.StronglyConnectedComponents(ComponentOptions{}, true)))
+	assert.Equal([][]string{
+		{"Alice", "Bob", "Carol"},
+		{"Dave", "Eve"},
+		{"Acme"},
+		{"Xavier"},
+		{"Yann"},
+		{"Zed"},
+	}, components(kg.WeaklyConnectedComponents(ComponentOptions{Predicates: []string{"KNOWS"}}, false)))
+	assert.Equal([]string{"Xavier"}, labels(kg.Orphans()))
+
+	report := kg.Health(ComponentOptions{}, true)
+	assert.Equal(9, report.Entities)
+	assert.Equal(6, report.Triples)
+	assert.Len(report.WeakComponents, 5)
+	assert.Len(report.StrongComponents, 7)
+	assert.Equal([]string{"Xavier", "Yann", "Zed"}, labels(report.Isolated))
+	assert.Equal([]string{"Xavier"}, labels(report.Orphans))
+	assert.Len(report.SelfLoops, 1)
+	assert.Equal("Zed", report.SelfLoops[0].F.(*Node).Lexical)
+
+	report = kg.Health(ComponentOptions{Graphs: []string{"draft"}}, true)
+	assert.Zero(report.Triples)
+	assert.Len(report.Isolated, 9)
+	assert.Empty(report.SelfLoops)
+
+	report = NewKG("empty").Health(ComponentOptions{}, true)
+	assert.Zero(report.Entities)
+	assert.Empty(report.WeakComponents)
+}
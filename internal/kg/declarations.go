@@ -0,0 +1,216 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PredicateDeclaration records properties of a predicate that hold whatever entities it links.
+// Unlike the schema, declarations do not restrict which predicates may be used.
+// Predicate names are compared case-insensitively.
+type PredicateDeclaration struct {
+	Name       string         `json:"name"`
+	Inverse    string         `json:"inverse,omitempty"`     // Predicate stating the same fact from the object to the subject
+	Symmetric  bool           `json:"symmetric,omitempty"`   // Whether the predicate states the same fact in both directions
+	MaxValues  int            `json:"max_values,omitempty"`  // Maximum number of distinct values per subject, unlimited if zero
+	OnConflict ConflictPolicy `json:"on_conflict,omitempty"` // What happens when a triple exceeds MaxValues
+}
+
+// Functional reports whether the predicate allows only one value per subject.
+func (declaration PredicateDeclaration) Functional() bool {
+	return declaration.MaxValues == 1
+}
+
+// DeclareInverse declares that inverse is the inverse of predicate, as child_of is the inverse of parent_of:
+// the triple (x, predicate, y) states the same fact as (y, inverse, x), and conversely.
+// Declaring a predicate as its own inverse declares it symmetric.
+// It returns an error if either predicate already has a different inverse or is symmetric.
+func (kg *KG) DeclareInverse(predicate, inverse string) error {
+	if predicate == "" || inverse == "" {
+		return fmt.Errorf("empty predicate in the declaration of %q as the inverse of %q", inverse, predicate)
+	}
+	if foldKey(predicate) == foldKey(inverse) {
+		return kg.DeclareSymmetric(predicate)
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	for _, pair := range [][2]string{{predicate, inverse}, {inverse, predicate}} {
+		if declaration := kg.declarations[foldKey(pair[0])]; declaration != nil {
+			if declaration.Symmetric {
+				return fmt.Errorf("predicate %q is symmetric and cannot have %q as its inverse", declaration.Name, pair[1])
+			}
+			if declaration.Inverse != "" && foldKey(declaration.Inverse) != foldKey(pair[1]) {
+				return fmt.Errorf("predicate %q already has %q as its inverse", declaration.Name, declaration.Inverse)
+			}
+		}
+	}
+
+	kg.declaration(predicate).Inverse = inverse
+	kg.declaration(inverse).Inverse = predicate
+	kg.refreshInverses(predicate, inverse)
+	return nil
+}
+
+// DeclareSymmetric declares that the predicate is symmetric, as married_to is:
+// the triple (x, predicate, y) states the same fact as (y, predicate, x).
+// It returns an error if the predicate already has a different inverse.
+func (kg *KG) DeclareSymmetric(predicate string) error {
+	if predicate == "" {
+		return fmt.Errorf("empty predicate in a symmetric declaration")
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	if declaration := kg.declarations[foldKey(predicate)]; declaration != nil && declaration.Inverse != "" {
+		return fmt.Errorf("predicate %q already has %q as its inverse and cannot be symmetric", declaration.Name, declaration.Inverse)
+	}
+
+	kg.declaration(predicate).Symmetric = true
+	kg.refreshInverses(predicate)
+	return nil
+}
+
+// RemoveDeclaration removes every property declared for the predicate.
+// The predicate declared as its inverse, if any, loses its inverse as well.
+// It returns an error if nothing is declared for the predicate.
+func (kg *KG) RemoveDeclaration(predicate string) error {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	declaration := kg.declarations[foldKey(predicate)]
+	if declaration == nil {
+		return fmt.Errorf("no declaration for predicate %q", predicate)
+	}
+
+	delete(kg.declarations, foldKey(predicate))
+	if declaration.Inverse != "" {
+		if inverse := kg.declarations[foldKey(declaration.Inverse)]; inverse != nil {
+			inverse.Inverse = ""
+			if *inverse == (PredicateDeclaration{Name: inverse.Name}) {
+				delete(kg.declarations, foldKey(declaration.Inverse))
+			}
+		}
+		kg.refreshInverses(declaration.Inverse)
+	}
+	kg.refreshInverses(predicate)
+	return nil
+}
+
+// Declaration returns a copy of the declaration of the predicate, or nil if nothing is declared for it.
+func (kg *KG) Declaration(predicate string) *PredicateDeclaration {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	declaration := kg.declarations[foldKey(predicate)]
+	if declaration == nil {
+		return nil
+	}
+	result := *declaration
+	return &result
+}
+
+// Declarations returns a copy of the predicate declarations of the graph, sorted by predicate name.
+func (kg *KG) Declarations() []PredicateDeclaration {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return kg.sortedDeclarations()
+}
+
+// sortedDeclarations returns a copy of the predicate declarations, sorted by predicate name.
+// The caller must hold at least a read lock.
+func (kg *KG) sortedDeclarations() []PredicateDeclaration {
+	var result []PredicateDeclaration
+	for _, declaration := range kg.declarations {
+		result = append(result, *declaration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// mergeDeclarations returns the properties declared for either predicate, named after the first one.
+// It returns an error if the predicates have different inverses or cardinalities,
+// or if one is symmetric and the other has an inverse.
+func mergeDeclarations(a, b PredicateDeclaration) (PredicateDeclaration, error) {
+	merged := a
+	if b.Inverse != "" {
+		if a.Inverse != "" && foldKey(a.Inverse) != foldKey(b.Inverse) {
+			return merged, fmt.Errorf("%q has %q as its inverse and %q has %q", a.Name, a.Inverse, b.Name, b.Inverse)
+		}
+		merged.Inverse = b.Inverse
+	}
+	merged.Symmetric = a.Symmetric || b.Symmetric
+	if merged.Symmetric && merged.Inverse != "" {
+		return merged, fmt.Errorf("a symmetric predicate cannot have %q as its inverse", merged.Inverse)
+	}
+	if b.MaxValues != 0 {
+		if a.MaxValues != 0 && (a.MaxValues != b.MaxValues || a.OnConflict != b.OnConflict) {
+			return merged, fmt.Errorf("%q and %q have different cardinalities", a.Name, b.Name)
+		}
+		merged.MaxValues, merged.OnConflict = b.MaxValues, b.OnConflict
+	}
+	return merged, nil
+}
+
+// declaration returns the declaration of the predicate, creating an empty one if needed.
+// The caller must hold the write lock.
+func (kg *KG) declaration(predicate string) *PredicateDeclaration {
+	if kg.declarations == nil {
+		kg.declarations = make(map[string]*PredicateDeclaration)
+	}
+	declaration := kg.declarations[foldKey(predicate)]
+	if declaration == nil {
+		declaration = &PredicateDeclaration{Name: predicate}
+		kg.declarations[foldKey(predicate)] = declaration
+	}
+	return declaration
+}
+
+// inverseOf returns the predicate declared as the inverse of the predicate, the predicate itself if it is symmetric,
+// or an empty string.
+// The caller must hold at least a read lock.
+func (kg *KG) inverseOf(predicate string) string {
+	declaration := kg.declarations[foldKey(predicate)]
+	switch {
+	case declaration == nil:
+		return ""
+	case declaration.Symmetric:
+		return predicate
+	default:
+		return declaration.Inverse
+	}
+}
+
+// refreshInverses records the declared inverse of the predicates on the stored predicates with these names.
+// The caller must hold the write lock.
+func (kg *KG) refreshInverses(predicates ...string) {
+	for _, predicate := range predicates {
+		for _, pred := range kg.lookupPredicates(predicate, false) {
+			pred.inverse = kg.inverseOf(pred.Subject)
+		}
+	}
+}
+
+// impliedInverses returns the reverse of the predicates that have a declared inverse, labeled with the inverse,
+// unless the reversed triple is stored as well. The reversed predicates do not belong to the graph.
+// Predicates with a literal object cannot be reversed.
+// The caller must hold at least a read lock.
+func (kg *KG) impliedInverses(predicates []*Predicate) []*Predicate {
+	var result []*Predicate
+	for _, pred := range predicates {
+		if pred.inverse == "" || pred.T.(*Node).IsLiteral() {
+			continue
+		}
+		reversed := pred.ReversedLine().(*Predicate)
+		if kg.duplicatePredicate(reversed) != nil {
+			continue
+		}
+		result = append(result, reversed)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package kg
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredicateDeclarations(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Alice", "parent_of", "Bob", true))
+	assert.NoError(kg.InsertTriple("Alice", "married_to", "Carol", true))
+	assert.NoError(kg.InsertTriple("Bob", "child_of", "Carol", true))
+
+	// Without declarations, facts are only answered in the direction they were stated
+	results, err := kg.QueryBySubject("Bob", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"child_of": {"Carol"}}, results)
+	predicates, err := kg.PredicatesFromTo("Carol", "Alice", true)
+	assert.NoError(err)
+	assert.Empty(predicates)
+	predicates, err = kg.PredicatesFromTo("Alice", "Bob", true)
+	assert.NoError(err)
+	reversed := predicates[0].ReversedEdge().(*Predicate)
+	assert.Equal("^parent_of", reversed.Subject, "The reverse of an undeclared predicate is marked with ^")
+	assert.Equal("parent_of", reversed.ReversedEdge().(*Predicate).Subject)
+
+	assert.NoError(kg.DeclareInverse("parent_of", "child_of"))
+	assert.NoError(kg.DeclareSymmetric("married_to"))
+	assert.NoError(kg.DeclareInverse("parent_of", "child_of"), "Declaring the same inverse again is a no-op")
+	assert.Error(kg.DeclareInverse("parent_of", "descendant_of"))
+	assert.Error(kg.DeclareInverse("married_to", "spouse_of"))
+	assert.Error(kg.DeclareSymmetric("child_of"))
+	assert.Equal([]PredicateDeclaration{
+		{Name: "child_of", Inverse: "parent_of"},
+		{Name: "married_to", Symmetric: true},
+		{Name: "parent_of", Inverse: "child_of"},
+	}, kg.Declarations())
+
+	// Facts are answered in both directions without being stored twice
+	results, err = kg.QueryBySubject("Bob", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"child_of": {"Alice", "Carol"}}, sortedValues(results))
+	results, err = kg.QueryByObject("Carol", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"married_to": {"Alice"}, "child_of": {"Bob"}}, sortedValues(results))
+	results, err = kg.QueryByObject("Bob", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"parent_of": {"Alice", "Carol"}}, sortedValues(results))
+	results, err = kg.QueryBySubject("Alice", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"married_to": {"Carol"}, "parent_of": {"Bob"}}, sortedValues(results))
+	assert.ElementsMatch([][3]string{
+		{"Alice", "parent_of", "Bob"},
+		{"Bob", "child_of", "Alice"},
+		{"Alice", "married_to", "Carol"},
+		{"Carol", "married_to", "Alice"},
+	}, kg.DescribeEntity("Alice", true))
+	predicates, err = kg.PredicatesFromTo("Carol", "Alice", true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal("married_to", predicates[0].Subject)
+	predicates, err = kg.PredicatesFromTo("Bob", "Alice", true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal("child_of", predicates[0].Subject)
+	assert.Len(kg.FindTriples("", "", "", true), 3, "Nothing is stored twice")
+
+	// A stored inverse is not repeated
+	assert.NoError(kg.InsertTriple("Carol", "parent_of", "Bob", true))
+	results, err = kg.QueryBySubject("Bob", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"child_of": {"Alice", "Carol"}}, sortedValues(results))
+
+	// ReversedEdge returns the declared inverse
+	predicates, err = kg.PredicatesFromTo("Alice", "Bob", true)
+	assert.NoError(err)
+	reversed = predicates[0].ReversedEdge().(*Predicate)
+	assert.Equal("child_of", reversed.Subject)
+	assert.Equal("Bob", reversed.From().(*Node).Lexical)
+	assert.Equal("Alice", reversed.To().(*Node).Lexical)
+
+	// Declarations are stored with the graph
+	var buf bytes.Buffer
+	assert.NoError(WriteTo(&buf, kg))
+	restored, err := ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(kg.Declarations(), restored.Declarations())
+	predicates, err = restored.PredicatesFromTo("Carol", "Alice", true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+
+	assert.NoError(kg.RemoveDeclaration("child_of"))
+	assert.Nil(kg.Declaration("parent_of"), "The inverse declaration is removed on both sides")
+	predicates, err = kg.PredicatesFromTo("Alice", "Bob", true)
+	assert.NoError(err)
+	assert.Equal("^parent_of", predicates[0].ReversedEdge().(*Predicate).Subject)
+	assert.Error(kg.RemoveDeclaration("child_of"))
+	predicates, err = kg.PredicatesFromTo("Bob", "Alice", true)
+	assert.NoError(err)
+	assert.Empty(predicates)
+}
+
+// sortedValues sorts the values of a query result, for comparison.
+func sortedValues(result map[string][]string) map[string][]string {
+	for _, values := range result {
+		sort.Strings(values)
+	}
+	return result
+}
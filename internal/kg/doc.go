@@ -27,6 +27,6 @@
 //	kg := NewKG()
 //	kg.InsertTriple("Go", "is", "Programming Language", true)
 //	kg.InsertTriple("Go", "created by", "Google", true)
-//	results := kg.QueryBySubject("Go", true)
+//	results, err := kg.QueryBySubject("Go", true)
 //	// results will contain predicates "is" and "created by" with their objects
 package kg
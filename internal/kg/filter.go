@@ -0,0 +1,342 @@
+package kg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTypeMismatch is returned when a filter compares values of incompatible types.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// Operator is a comparison operator of a Filter.
+type Operator string
+
+// The operators supported by filters.
+const (
+	OpEqual        Operator = "="
+	OpNotEqual     Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpBetween      Operator = "between"
+	OpPrefix       Operator = "prefix"
+)
+
+// valueKind groups the datatypes that can be compared with each other.
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindTime
+	kindBoolean
+)
+
+// String returns the name of the kind used in error messages.
+func (kind valueKind) String() string {
+	switch kind {
+	case kindNumber:
+		return "number"
+	case kindTime:
+		return "date"
+	case kindBoolean:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// kindOf returns the kind of values of the datatype.
+func kindOf(datatype Datatype) valueKind {
+	switch datatype {
+	case DatatypeInteger, DatatypeDecimal:
+		return kindNumber
+	case DatatypeDate, DatatypeDateTime:
+		return kindTime
+	case DatatypeBoolean:
+		return kindBoolean
+	default:
+		return kindString
+	}
+}
+
+// Filter is a condition on the object of a triple, such as "> 1990",
+// "between 2000-01-01 and 2010-12-31" or "prefix Py".
+// Numbers are compared numerically, dates and date-times chronologically,
+// and strings lexicographically.
+type Filter struct {
+	Operator Operator
+	Value    string   // Operand, or lower bound of a between filter
+	Upper    string   // Upper bound of a between filter
+	Datatype Datatype // Datatype of the operands
+}
+
+// ParseFilter parses a filter expression of the form "<operator> <value>",
+// "between <lower> and <upper>" or "prefix <value>".
+// The supported operators are =, !=, <, <=, > and >=.
+// The datatype of the operands is inferred from their syntax; a quoted operand is a string.
+func ParseFilter(expression string) (*Filter, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, errors.New("empty filter expression")
+	}
+
+	var filter *Filter
+	lower := strings.ToLower(expression)
+	switch {
+	case strings.HasPrefix(lower, "between "):
+		bounds := expression[len("between "):]
+		i := strings.Index(strings.ToLower(bounds), " and ")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid filter %q: expected between <lower> and <upper>", expression)
+		}
+		lowerValue, lowerType := parseOperand(bounds[:i])
+		upperValue, upperType := parseOperand(bounds[i+len(" and "):])
+		if kindOf(lowerType) != kindOf(upperType) {
+			return nil, fmt.Errorf("%w: invalid filter %q: bounds are a %s and a %s", ErrTypeMismatch, expression, kindOf(lowerType), kindOf(upperType))
+		}
+		if lowerType != upperType && kindOf(lowerType) == kindNumber {
+			lowerType = DatatypeDecimal
+		}
+		filter = &Filter{Operator: OpBetween, Value: lowerValue, Upper: upperValue, Datatype: lowerType}
+	case strings.HasPrefix(lower, "prefix "):
+		value, _ := parseOperand(expression[len("prefix "):])
+		filter = &Filter{Operator: OpPrefix, Value: value, Datatype: DatatypeString}
+	default:
+		var operator Operator
+		for _, op := range []Operator{OpLessEqual, OpGreaterEqual, OpNotEqual, "==", OpLess, OpGreater, OpEqual} {
+			if strings.HasPrefix(expression, string(op)) {
+				operator = op
+				break
+			}
+		}
+		if operator == "" {
+			return nil, fmt.Errorf("invalid filter %q: unknown operator", expression)
+		}
+		value, datatype := parseOperand(expression[len(operator):])
+		if operator == "==" {
+			operator = OpEqual
+		}
+		filter = &Filter{Operator: operator, Value: value, Datatype: datatype}
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// parseOperand returns the value of an operand and its inferred datatype.
+func parseOperand(operand string) (string, Datatype) {
+	operand = strings.TrimSpace(operand)
+	if len(operand) >= 2 && strings.HasPrefix(operand, `"`) && strings.HasSuffix(operand, `"`) {
+		return operand[1 : len(operand)-1], DatatypeString
+	}
+	for _, datatype := range []Datatype{DatatypeInteger, DatatypeDecimal, DatatypeDate, DatatypeDateTime, DatatypeBoolean} {
+		if literal, err := NewLiteral(operand, datatype, ""); err == nil {
+			return literal.Value, datatype
+		}
+	}
+	return operand, DatatypeString
+}
+
+// Validate checks that the operator applies to the datatype of the operands
+// and that the operands are valid values of that datatype.
+func (filter *Filter) Validate() error {
+	if filter.Value == "" && filter.Operator != OpEqual && filter.Operator != OpNotEqual {
+		return fmt.Errorf("invalid filter: missing value for operator %s", filter.Operator)
+	}
+
+	datatype := filter.Datatype
+	if datatype == "" {
+		datatype = DatatypeString
+	}
+	kind := kindOf(datatype)
+
+	switch filter.Operator {
+	case OpEqual, OpNotEqual:
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpBetween:
+		if kind == kindBoolean {
+			return fmt.Errorf("%w: operator %s cannot be applied to a boolean", ErrTypeMismatch, filter.Operator)
+		}
+	case OpPrefix:
+		if kind != kindString {
+			return fmt.Errorf("%w: operator %s can only be applied to a string, not to a %s", ErrTypeMismatch, filter.Operator, kind)
+		}
+	default:
+		return fmt.Errorf("invalid filter: unknown operator %q", filter.Operator)
+	}
+
+	if kind != kindString {
+		for _, operand := range filter.operands() {
+			if _, err := NewLiteral(operand, datatype, ""); err != nil {
+				return fmt.Errorf("invalid filter: %w", err)
+			}
+		}
+	}
+
+	if filter.Operator == OpBetween {
+		cmp, err := compareValues(kind, filter.Value, filter.Upper)
+		if err != nil {
+			return err
+		}
+		if cmp > 0 {
+			return fmt.Errorf("invalid filter: lower bound %s is greater than upper bound %s", filter.Value, filter.Upper)
+		}
+	}
+
+	return nil
+}
+
+// operands returns the operands of the filter.
+func (filter *Filter) operands() []string {
+	if filter.Operator == OpBetween {
+		return []string{filter.Value, filter.Upper}
+	}
+	return []string{filter.Value}
+}
+
+// String returns the expression of the filter.
+func (filter *Filter) String() string {
+	switch filter.Operator {
+	case OpBetween:
+		return fmt.Sprintf("between %s and %s", filter.Value, filter.Upper)
+	default:
+		return fmt.Sprintf("%s %s", filter.Operator, filter.Value)
+	}
+}
+
+// Match returns true if the node satisfies the filter.
+// Entities are only compared with string operands; they never match filters on other types.
+// A literal of a type that cannot be compared with the operands returns an ErrTypeMismatch error,
+// except for the equality of a string operand, which compares lexical values.
+// The caseSensitiveSearch parameter determines if string comparisons are case-sensitive.
+func (filter *Filter) Match(node *Node, caseSensitiveSearch bool) (bool, error) {
+	datatype := filter.Datatype
+	if datatype == "" {
+		datatype = DatatypeString
+	}
+	kind := kindOf(datatype)
+
+	value := node.Lexical
+	operands := filter.operands()
+	if kind == kindString && !caseSensitiveSearch {
+		value = strings.ToLower(value)
+		for i := range operands {
+			operands[i] = strings.ToLower(operands[i])
+		}
+	}
+
+	if filter.Operator == OpPrefix {
+		return strings.HasPrefix(value, operands[0]), nil
+	}
+
+	nodeKind := kindOf(node.Datatype)
+	if nodeKind != kind {
+		switch {
+		case !node.IsLiteral():
+			// An entity has no typed value to compare with
+			return false, nil
+		case kind == kindString && filter.Operator == OpEqual:
+			return value == operands[0], nil
+		case kind == kindString && filter.Operator == OpNotEqual:
+			return value != operands[0], nil
+		default:
+			return false, fmt.Errorf("%w: cannot compare %s literal %q with %s %q", ErrTypeMismatch, node.Datatype, node.Lexical, kind, filter.Value)
+		}
+	}
+
+	cmp, err := compareValues(kind, value, operands[0])
+	if err != nil {
+		return false, err
+	}
+
+	switch filter.Operator {
+	case OpEqual:
+		return cmp == 0, nil
+	case OpNotEqual:
+		return cmp != 0, nil
+	case OpLess:
+		return cmp < 0, nil
+	case OpLessEqual:
+		return cmp <= 0, nil
+	case OpGreater:
+		return cmp > 0, nil
+	case OpGreaterEqual:
+		return cmp >= 0, nil
+	case OpBetween:
+		upper, err := compareValues(kind, value, operands[1])
+		if err != nil {
+			return false, err
+		}
+		return cmp >= 0 && upper <= 0, nil
+	default:
+		return false, fmt.Errorf("invalid filter: unknown operator %q", filter.Operator)
+	}
+}
+
+// compareValues compares two values of the same kind given in their lexical form.
+// It returns a negative number if a < b, zero if a == b and a positive number if a > b.
+func compareValues(kind valueKind, a, b string) (int, error) {
+	switch kind {
+	case kindNumber:
+		ai, errA := strconv.ParseInt(a, 10, 64)
+		bi, errB := strconv.ParseInt(b, 10, 64)
+		if errA == nil && errB == nil {
+			return compareOrdered(ai, bi), nil
+		}
+		af, errA := strconv.ParseFloat(a, 64)
+		bf, errB := strconv.ParseFloat(b, 64)
+		if errA != nil || errB != nil {
+			return 0, fmt.Errorf("%w: cannot compare %q with %q as numbers", ErrTypeMismatch, a, b)
+		}
+		return compareOrdered(af, bf), nil
+	case kindTime:
+		at, errA := parseTime(a)
+		bt, errB := parseTime(b)
+		if errA != nil || errB != nil {
+			return 0, fmt.Errorf("%w: cannot compare %q with %q as dates", ErrTypeMismatch, a, b)
+		}
+		return at.Compare(bt), nil
+	case kindBoolean:
+		ab, errA := strconv.ParseBool(a)
+		bb, errB := strconv.ParseBool(b)
+		if errA != nil || errB != nil {
+			return 0, fmt.Errorf("%w: cannot compare %q with %q as booleans", ErrTypeMismatch, a, b)
+		}
+		if ab == bb {
+			return 0, nil
+		}
+		if !ab {
+			return -1, nil
+		}
+		return 1, nil
+	default:
+		return strings.Compare(a, b), nil
+	}
+}
+
+// compareOrdered compares two ordered values.
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parseTime parses a date or a date-time literal.
+// Dates are considered at midnight UTC.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(dateLayout, value)
+}
@@ -0,0 +1,106 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   *Filter
+		wantErr    bool
+	}{
+		{expression: "> 1990", expected: &Filter{Operator: OpGreater, Value: "1990", Datatype: DatatypeInteger}},
+		{expression: "<=2.5", expected: &Filter{Operator: OpLessEqual, Value: "2.5", Datatype: DatatypeDecimal}},
+		{expression: "== true", expected: &Filter{Operator: OpEqual, Value: "true", Datatype: DatatypeBoolean}},
+		{expression: `!= "1990"`, expected: &Filter{Operator: OpNotEqual, Value: "1990", Datatype: DatatypeString}},
+		{expression: "between 2000-01-01 and 2010-12-31", expected: &Filter{Operator: OpBetween, Value: "2000-01-01", Upper: "2010-12-31", Datatype: DatatypeDate}},
+		{expression: "BETWEEN 1 AND 2.5", expected: &Filter{Operator: OpBetween, Value: "1", Upper: "2.5", Datatype: DatatypeDecimal}},
+		{expression: "prefix Py", expected: &Filter{Operator: OpPrefix, Value: "Py", Datatype: DatatypeString}},
+		{expression: "", wantErr: true},
+		{expression: "~ 1990", wantErr: true},
+		{expression: "> true", wantErr: true},
+		{expression: "between 1990 and 2000-01-01", wantErr: true},
+		{expression: "between 2000 and 1990", wantErr: true},
+		{expression: "between 1990", wantErr: true},
+		{expression: "prefix", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			filter, err := ParseFilter(tc.expression)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, filter)
+		})
+	}
+}
+
+func TestMatchTriplesWithFilter(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, false))
+	assert.NoError(kg.Insert(Triple{Subject: "Go", Predicate: "first_released", Object: "2009", ObjectType: DatatypeInteger}, false))
+	assert.NoError(kg.Insert(Triple{Subject: "C", Predicate: "first_released", Object: "1972", ObjectType: DatatypeInteger}, false))
+	assert.NoError(kg.Insert(Triple{Subject: "Go", Predicate: "version_date", Object: "2024-02-06", ObjectType: DatatypeDate}, false))
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "version_date", Object: "2023-10-02T00:00:00Z", ObjectType: DatatypeDateTime}, false))
+	assert.NoError(kg.InsertTriple("Python", "influenced", "Go", false))
+	assert.NoError(kg.InsertTriple("Python", "influenced", "Pyret", false))
+
+	subjects := func(predicates []*Predicate) []string {
+		result := make([]string, 0, len(predicates))
+		for _, pred := range predicates {
+			result = append(result, pred.F.(*Node).Lexical)
+		}
+		return result
+	}
+
+	filter, err := ParseFilter("> 1990")
+	assert.NoError(err)
+	predicates, err := kg.MatchTriples(TriplePattern{Predicate: "first_released", Filter: filter}, false)
+	assert.NoError(err)
+	assert.ElementsMatch([]string{"Python", "Go"}, subjects(predicates))
+
+	filter, err = ParseFilter("between 2023-01-01 and 2023-12-31")
+	assert.NoError(err)
+	predicates, err = kg.MatchTriples(TriplePattern{Predicate: "version_date", Filter: filter}, false)
+	assert.NoError(err)
+	assert.Equal([]string{"Python"}, subjects(predicates), "Dates and date-times should be compared chronologically")
+
+	filter, err = ParseFilter("prefix py")
+	assert.NoError(err)
+	predicates, err = kg.MatchTriples(TriplePattern{Subject: "Python", Predicate: "influenced", Filter: filter}, false)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal("Pyret", predicates[0].T.(*Node).Lexical)
+
+	// Entities never match a filter on numbers
+	filter, err = ParseFilter(">= 0")
+	assert.NoError(err)
+	predicates, err = kg.MatchTriples(TriplePattern{Predicate: "influenced", Filter: filter}, false)
+	assert.NoError(err)
+	assert.Empty(predicates)
+
+	// Comparing a date with a number is an error
+	filter, err = ParseFilter("> 1990")
+	assert.NoError(err)
+	_, err = kg.MatchTriples(TriplePattern{Predicate: "version_date", Filter: filter}, false)
+	assert.ErrorIs(err, ErrTypeMismatch)
+
+	// Without a predicate, the values of other types are skipped
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "nickname", Object: "Py", ObjectType: DatatypeString}, false))
+	predicates, err = kg.MatchTriples(TriplePattern{Subject: "Python", Filter: filter}, false)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal("1991", predicates[0].T.(*Node).Lexical)
+	_, err = kg.MatchTriples(TriplePattern{Subject: "Python", Predicate: "nickname", Filter: filter}, false)
+	assert.ErrorIs(err, ErrTypeMismatch, "A bound predicate reports the mismatch")
+	_, err = kg.MatchTriples(TriplePattern{Subject: "Go", Object: "2024-02-06", Filter: filter}, false)
+	assert.ErrorIs(err, ErrTypeMismatch, "No value can be compared")
+}
@@ -0,0 +1,606 @@
+package kg
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultHistoryLimit is the number of revisions kept in the history of a graph whose HistoryLimit is zero.
+const DefaultHistoryLimit = 100
+
+// ChangeKind tells whether a change inserted or removed a node or a triple, or updated the settings of the graph.
+type ChangeKind string
+
+const (
+	ChangeInsert ChangeKind = "insert"
+	ChangeRemove ChangeKind = "remove"
+	ChangeUpdate ChangeKind = "update" // The schema, the rules or the predicate declarations changed
+)
+
+// graphSettings are the parts of a graph that govern its triples rather than state facts:
+// its schema, its rules and its predicate declarations.
+type graphSettings struct {
+	schema       *Schema
+	rules        []Rule
+	declarations []PredicateDeclaration // Sorted by predicate name
+}
+
+// SettingsChange holds the settings of the graph changed by an update, as they were before and after it.
+// The settings left unchanged are not recorded.
+type SettingsChange struct {
+	Schema       *SchemaChange       // nil if the schema did not change
+	Rules        *RulesChange        // nil if the rules did not change
+	Declarations []DeclarationChange // Sorted by predicate name
+}
+
+// SchemaChange is a change of the schema of the graph. A nil schema means that the graph had none.
+type SchemaChange struct {
+	Before *Schema
+	After  *Schema
+}
+
+// RulesChange is a change of the rules of the graph.
+type RulesChange struct {
+	Before []Rule
+	After  []Rule
+}
+
+// DeclarationChange is a change of the declaration of a predicate. A nil declaration means that the predicate
+// was not declared.
+type DeclarationChange struct {
+	Name   string
+	Before *PredicateDeclaration
+	After  *PredicateDeclaration
+}
+
+// Change is an insertion or a removal of a node or of a triple, or an update of the settings of the graph.
+// Modifying a node or a triple, for instance renaming an entity or updating the provenance of a triple,
+// is recorded as the removal of its former state followed by the insertion of the new one.
+type Change struct {
+	Kind      ChangeKind
+	Node      *Node                  // Node inserted or removed, nil for a triple or the settings
+	Triple    *SerializablePredicate // Triple inserted or removed, nil for a node or the settings
+	Statement [3]string              // Subject, predicate and object of the triple as they read at the time of the change
+	Settings  *SettingsChange        // Settings changed by an update, nil for a node or a triple
+}
+
+// String describes the change, such as "inserted (Alice, works_for, Acme)", "removed entity Bob"
+// or "changed the rules".
+func (change Change) String() string {
+	if change.Kind == ChangeUpdate {
+		return change.Settings.String()
+	}
+	verb := "inserted"
+	if change.Kind == ChangeRemove {
+		verb = "removed"
+	}
+	if change.Node != nil {
+		if change.Node.IsLiteral() {
+			return verb + " literal " + change.Node.String()
+		}
+		description := verb + " entity " + change.Node.Lexical
+		if change.Node.Key != "" && !strings.HasPrefix(change.Node.Key, generatedKeyPrefix) {
+			description += " <" + change.Node.Key + ">"
+		}
+		if len(change.Node.Aliases) > 0 {
+			description += " (also known as " + strings.Join(change.Node.Aliases, ", ") + ")"
+		}
+		return description
+	}
+	description := verb + " (" + strings.Join(change.Statement[:], ", ") + ")"
+	if change.Triple.Graph != DefaultGraph {
+		description += " in graph " + change.Triple.Graph
+	}
+	return description
+}
+
+// Revision is a numbered set of changes made to the graph at once, such as by a single tool call.
+// Revisions are numbered from 1; revision 0 is the graph as it was when its history started,
+// empty for a new graph.
+type Revision struct {
+	Number  int
+	Time    time.Time
+	Changes []Change // Removals first, then insertions, then the update of the settings
+}
+
+// journal collects the state of the nodes and triples a modification touches, as it was before the modification,
+// so that its changes are found without comparing the whole graph.
+type journal struct {
+	nodes    map[int64]*Node                  // Copy of each touched node before the modification, nil if it did not exist
+	triples  map[int64]*SerializablePredicate // Copy of each touched triple before the modification, nil if it did not exist
+	live     map[int64]*Predicate             // Last predicate touched with each triple ID
+	settings graphSettings                    // Settings before the modification
+}
+
+// graphState is the content of the graph tracked by its history: copies of its nodes and triples, by ID,
+// and its settings.
+type graphState struct {
+	nodes    map[int64]*Node
+	triples  map[int64]SerializablePredicate
+	settings graphSettings
+}
+
+// RecordChanges applies modify to the graph and records the nodes and triples it inserted and removed,
+// and the changes of the schema, the rules and the predicate declarations, as a new revision of the graph,
+// so that the changes can be undone by UndoLastChange and the graph reconstructed as it was before them by AtRevision.
+// The changes are collected as the nodes and triples are modified, so that recording them costs
+// in proportion to the size of the changes rather than of the graph.
+// Recorded modifications are applied one at a time, so that a revision only holds the changes of its modification
+// when several goroutines record changes; modify must not call RecordChanges.
+// Nothing is recorded if the graph is left unchanged, if modify undoes changes,
+// or if HistoryLimit is negative. The oldest revisions are forgotten beyond HistoryLimit revisions.
+// The changes made before modify fails are recorded as well; the error of modify is returned.
+// It returns the recorded revision, or nil if nothing was recorded.
+func (kg *KG) RecordChanges(modify func(*KG) error) (*Revision, error) {
+	kg.recording.Lock()
+	defer kg.recording.Unlock()
+
+	if kg.HistoryLimit < 0 {
+		return nil, modify(kg)
+	}
+
+	kg.mu.Lock()
+	kg.journal = &journal{
+		nodes:    make(map[int64]*Node),
+		triples:  make(map[int64]*SerializablePredicate),
+		live:     make(map[int64]*Predicate),
+		settings: kg.currentSettings(),
+	}
+	revision := kg.revision()
+	kg.mu.Unlock()
+
+	err := modify(kg)
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	recorded := kg.journal
+	kg.journal = nil
+	if kg.revision() != revision {
+		return nil, err
+	}
+	changes := kg.journalChanges(recorded)
+	if len(changes) == 0 {
+		return nil, err
+	}
+	kg.history = append(kg.history, Revision{
+		Number:  revision + 1,
+		Time:    time.Now(),
+		Changes: changes,
+	})
+	result := kg.history[len(kg.history)-1]
+
+	// Forget the oldest revisions beyond the limit
+	limit := kg.HistoryLimit
+	if limit == 0 {
+		limit = DefaultHistoryLimit
+	}
+	if len(kg.history) > limit {
+		kg.history = slices.Clone(kg.history[len(kg.history)-limit:])
+	}
+	return &result, err
+}
+
+// Revision returns the number of the current revision of the graph, 0 if no change was recorded.
+func (kg *KG) Revision() int {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return kg.revision()
+}
+
+// revision returns the number of the current revision of the graph.
+// The caller must hold at least a read lock.
+func (kg *KG) revision() int {
+	if len(kg.history) == 0 {
+		return 0
+	}
+	return kg.history[len(kg.history)-1].Number
+}
+
+// History returns the recorded revisions of the graph, oldest first.
+func (kg *KG) History() []Revision {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return slices.Clone(kg.history)
+}
+
+// AtRevision returns a copy of the graph as it was at the given revision, with its settings at that revision
+// and the history up to that revision.
+// It returns an error if the revision is not recorded.
+func (kg *KG) AtRevision(revision int) (*KG, error) {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return kg.atRevision(revision)
+}
+
+// UndoLastChange restores the graph, including its schema, rules and predicate declarations,
+// as it was before its last revision and removes that revision from the history.
+// It returns the revision undone, or an error if no change is recorded.
+func (kg *KG) UndoLastChange() (*Revision, error) {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	if len(kg.history) == 0 {
+		return nil, fmt.Errorf("no change to undo")
+	}
+	last := kg.history[len(kg.history)-1]
+	kg.indexPending()
+	kg.revert(last)
+	kg.history = kg.history[:len(kg.history)-1]
+	return &last, nil
+}
+
+// atRevision returns a copy of the graph as it was at the given revision.
+// The caller must hold at least a read lock.
+func (kg *KG) atRevision(revision int) (*KG, error) {
+	current := kg.revision()
+	first := current - len(kg.history)
+	if revision < first || revision > current {
+		return nil, fmt.Errorf("revision %d is not recorded: the history goes from revision %d to %d", revision, first, current)
+	}
+
+	// Undo the later revisions, last change first
+	state := kg.state()
+	kept := len(kg.history) - (current - revision)
+	for i := len(kg.history) - 1; i >= kept; i-- {
+		changes := kg.history[i].Changes
+		for j := len(changes) - 1; j >= 0; j-- {
+			state.undo(changes[j])
+		}
+	}
+
+	serialKG := SerializableKG{
+		Nodes:         state.nodes,
+		Edges:         make([]SerializablePredicate, 0, len(state.triples)),
+		CurrentID:     kg.currentID,
+		CurrentLineID: kg.currentLineID,
+		Schema:        state.settings.schema,
+		Rules:         state.settings.rules,
+		Declarations:  state.settings.declarations,
+		History:       slices.Clone(kg.history[:kept]),
+	}
+	for _, triple := range state.triples {
+		serialKG.Edges = append(serialKG.Edges, triple)
+	}
+	return newKGFromSerializable(serialKG), nil
+}
+
+// recordNode records the node in the journal of the modification in progress, if any,
+// before it is created, changed or removed.
+// The caller must hold the write lock.
+func (kg *KG) recordNode(node *Node) {
+	if kg.journal == nil {
+		return
+	}
+	if _, touched := kg.journal.nodes[node.ID()]; touched {
+		return
+	}
+	var before *Node
+	if kg.nodes[node.ID()] == node {
+		before = copyNode(node)
+	}
+	kg.journal.nodes[node.ID()] = before
+}
+
+// recordTriple records the predicate in the journal of the modification in progress, if any,
+// before it is added, changed or removed.
+// The caller must hold the write lock.
+func (kg *KG) recordTriple(pred *Predicate) {
+	if kg.journal == nil {
+		return
+	}
+	kg.journal.live[pred.ID()] = pred
+	if _, touched := kg.journal.triples[pred.ID()]; touched {
+		return
+	}
+	var before *SerializablePredicate
+	if kg.isStored(pred) {
+		triple := newSerializablePredicate(pred)
+		before = &triple
+	}
+	kg.journal.triples[pred.ID()] = before
+}
+
+// isStored reports whether the predicate is stored in the graph.
+// The caller must hold at least a read lock.
+func (kg *KG) isStored(pred *Predicate) bool {
+	return slices.Contains(kg.from[pred.F.ID()][pred.T.ID()], pred)
+}
+
+// journalChanges returns the changes recorded in the journal: the removals of triples and nodes,
+// then the insertions of nodes and triples, each sorted by ID, then the update of the settings.
+// The caller must hold at least a read lock.
+func (kg *KG) journalChanges(j *journal) []Change {
+	// The labels of the nodes as they read before the modification
+	labelBefore := func(id int64) string {
+		if node, touched := j.nodes[id]; touched && node != nil {
+			return node.String()
+		}
+		if node := kg.nodes[id]; node != nil {
+			return node.String()
+		}
+		return ""
+	}
+	labelAfter := func(id int64) string {
+		if node := kg.nodes[id]; node != nil {
+			return node.String()
+		}
+		return ""
+	}
+
+	var removedTriples, removedNodes, insertedNodes, insertedTriples []Change
+	for id, before := range j.nodes {
+		after := kg.nodes[id]
+		if before != nil && after != nil && sameNode(before, after) {
+			continue
+		}
+		if before != nil {
+			removedNodes = append(removedNodes, Change{Kind: ChangeRemove, Node: before})
+		}
+		if after != nil {
+			insertedNodes = append(insertedNodes, Change{Kind: ChangeInsert, Node: copyNode(after)})
+		}
+	}
+	for id, before := range j.triples {
+		var after *SerializablePredicate
+		if pred := j.live[id]; kg.isStored(pred) {
+			triple := newSerializablePredicate(pred)
+			after = &triple
+		}
+		if before != nil && after != nil && *before == *after {
+			continue
+		}
+		if before != nil {
+			statement := [3]string{labelBefore(before.FromID), before.Subject, labelBefore(before.ToID)}
+			removedTriples = append(removedTriples, Change{Kind: ChangeRemove, Triple: before, Statement: statement})
+		}
+		if after != nil {
+			statement := [3]string{labelAfter(after.FromID), after.Subject, labelAfter(after.ToID)}
+			insertedTriples = append(insertedTriples, Change{Kind: ChangeInsert, Triple: after, Statement: statement})
+		}
+	}
+
+	var changes []Change
+	for _, group := range [][]Change{removedTriples, removedNodes, insertedNodes, insertedTriples} {
+		sort.Slice(group, func(i, k int) bool {
+			return group[i].id() < group[k].id()
+		})
+		changes = append(changes, group...)
+	}
+	if update := j.settings.diff(kg.currentSettings()); update != nil {
+		changes = append(changes, Change{Kind: ChangeUpdate, Settings: update})
+	}
+	return changes
+}
+
+// revert undoes the changes of the revision on the graph, last change first.
+// The caller must hold the write lock.
+func (kg *KG) revert(revision Revision) {
+	// A node both removed and inserted by the revision was modified: it is restored in place,
+	// so that the triples pointing to it keep pointing to the node of the graph
+	modified := make(map[int64]bool)
+	for _, change := range revision.Changes {
+		if change.Node != nil && change.Kind == ChangeRemove {
+			modified[change.Node.ID()] = true
+		}
+	}
+
+	for i := len(revision.Changes) - 1; i >= 0; i-- {
+		change := revision.Changes[i]
+		switch {
+		case change.Kind == ChangeUpdate:
+			kg.restoreSettings(change.Settings.revert(kg.currentSettings()))
+		case change.Node != nil && change.Kind == ChangeInsert:
+			if node := kg.nodes[change.Node.ID()]; node != nil && !modified[node.ID()] {
+				kg.recordNode(node)
+				kg.removeNode(node)
+			}
+		case change.Node != nil:
+			node := kg.nodes[change.Node.ID()]
+			if node == nil {
+				node = copyNode(change.Node)
+				kg.recordNode(node)
+				kg.nodes[node.ID()] = node
+			} else {
+				kg.recordNode(node)
+				kg.unindexNode(node)
+				*node = *copyNode(change.Node)
+			}
+			kg.indexNode(node)
+		case change.Kind == ChangeInsert:
+			for _, pred := range kg.from[change.Triple.FromID][change.Triple.ToID] {
+				if pred.Identifier == change.Triple.ID {
+					kg.removePredicate(pred)
+					break
+				}
+			}
+		default:
+			if pred := kg.newPredicate(*change.Triple); pred != nil {
+				kg.addPredicate(pred)
+			}
+		}
+	}
+}
+
+// currentSettings returns the settings of the graph.
+// The caller must hold at least a read lock.
+func (kg *KG) currentSettings() graphSettings {
+	return graphSettings{
+		schema:       kg.schema,
+		rules:        kg.rules,
+		declarations: kg.sortedDeclarations(),
+	}
+}
+
+// restoreSettings makes the settings those of the graph.
+// The caller must hold the write lock.
+func (kg *KG) restoreSettings(settings graphSettings) {
+	var names []string
+	for _, declaration := range kg.declarations {
+		names = append(names, declaration.Name)
+	}
+	kg.schema = settings.schema
+	kg.rules = settings.rules
+	kg.invalidateInferred()
+	kg.declarations = nil
+	for _, declaration := range settings.declarations {
+		*kg.declaration(declaration.Name) = declaration
+		names = append(names, declaration.Name)
+	}
+	kg.refreshInverses(names...)
+}
+
+// diff returns the settings changed from the settings to the other ones, or nil if they are the same.
+func (settings graphSettings) diff(other graphSettings) *SettingsChange {
+	var change SettingsChange
+	if !reflect.DeepEqual(settings.schema, other.schema) {
+		change.Schema = &SchemaChange{Before: settings.schema, After: other.schema}
+	}
+	if !slices.EqualFunc(settings.rules, other.rules, func(a, b Rule) bool { return reflect.DeepEqual(a, b) }) {
+		change.Rules = &RulesChange{Before: settings.rules, After: other.rules}
+	}
+
+	declarations := make(map[string]*DeclarationChange)
+	for _, declaration := range settings.declarations {
+		declarations[foldKey(declaration.Name)] = &DeclarationChange{Name: declaration.Name, Before: &declaration}
+	}
+	for _, declaration := range other.declarations {
+		if declarations[foldKey(declaration.Name)] == nil {
+			declarations[foldKey(declaration.Name)] = &DeclarationChange{Name: declaration.Name}
+		}
+		declarations[foldKey(declaration.Name)].After = &declaration
+	}
+	for _, declaration := range declarations {
+		if declaration.Before == nil || declaration.After == nil || *declaration.Before != *declaration.After {
+			change.Declarations = append(change.Declarations, *declaration)
+		}
+	}
+	sort.Slice(change.Declarations, func(i, j int) bool {
+		return change.Declarations[i].Name < change.Declarations[j].Name
+	})
+
+	if change.Schema == nil && change.Rules == nil && len(change.Declarations) == 0 {
+		return nil
+	}
+	return &change
+}
+
+// revert returns the settings as they were before the change.
+func (change *SettingsChange) revert(settings graphSettings) graphSettings {
+	if change.Schema != nil {
+		settings.schema = change.Schema.Before
+	}
+	if change.Rules != nil {
+		settings.rules = change.Rules.Before
+	}
+	if len(change.Declarations) == 0 {
+		return settings
+	}
+
+	declarations := make(map[string]PredicateDeclaration)
+	for _, declaration := range settings.declarations {
+		declarations[foldKey(declaration.Name)] = declaration
+	}
+	for _, declaration := range change.Declarations {
+		delete(declarations, foldKey(declaration.Name))
+		if declaration.Before != nil {
+			declarations[foldKey(declaration.Name)] = *declaration.Before
+		}
+	}
+	settings.declarations = make([]PredicateDeclaration, 0, len(declarations))
+	for _, declaration := range declarations {
+		settings.declarations = append(settings.declarations, declaration)
+	}
+	sort.Slice(settings.declarations, func(i, j int) bool {
+		return settings.declarations[i].Name < settings.declarations[j].Name
+	})
+	return settings
+}
+
+// String describes what the update changed, such as "changed the rules and the declarations of child_of, parent_of".
+func (change *SettingsChange) String() string {
+	var parts []string
+	if change.Schema != nil {
+		parts = append(parts, "the schema")
+	}
+	if change.Rules != nil {
+		parts = append(parts, "the rules")
+	}
+	if len(change.Declarations) > 0 {
+		names := make([]string, len(change.Declarations))
+		for i, declaration := range change.Declarations {
+			names[i] = declaration.Name
+		}
+		parts = append(parts, "the declarations of "+strings.Join(names, ", "))
+	}
+	if len(parts) == 0 {
+		return "changed the settings"
+	}
+	if len(parts) == 1 {
+		return "changed " + parts[0]
+	}
+	return "changed " + strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// state returns copies of the nodes and triples of the graph, and its settings.
+// The caller must hold at least a read lock.
+func (kg *KG) state() graphState {
+	state := graphState{
+		nodes:    make(map[int64]*Node, len(kg.nodes)),
+		triples:  make(map[int64]SerializablePredicate),
+		settings: kg.currentSettings(),
+	}
+	for id, node := range kg.nodes {
+		if node != nil {
+			state.nodes[id] = copyNode(node)
+		}
+	}
+	for _, pred := range kg.allPredicates() {
+		state.triples[pred.ID()] = newSerializablePredicate(pred)
+	}
+	return state
+}
+
+// undo reverts the change in the state.
+func (state *graphState) undo(change Change) {
+	switch {
+	case change.Kind == ChangeUpdate:
+		state.settings = change.Settings.revert(state.settings)
+	case change.Node != nil && change.Kind == ChangeInsert:
+		delete(state.nodes, change.Node.ID())
+	case change.Node != nil:
+		state.nodes[change.Node.ID()] = copyNode(change.Node)
+	case change.Kind == ChangeInsert:
+		delete(state.triples, change.Triple.ID)
+	default:
+		state.triples[change.Triple.ID] = *change.Triple
+	}
+}
+
+// id returns the ID of the node or the triple changed.
+func (change Change) id() int64 {
+	if change.Node != nil {
+		return change.Node.ID()
+	}
+	return change.Triple.ID
+}
+
+// copyNode returns a copy of the node that does not share its aliases.
+func copyNode(node *Node) *Node {
+	result := *node
+	result.Aliases = slices.Clone(node.Aliases)
+	return &result
+}
+
+// sameNode reports whether two nodes have the same content.
+func sameNode(a, b *Node) bool {
+	return a.Identifier == b.Identifier && a.Key == b.Key && a.Lexical == b.Lexical &&
+		a.Datatype == b.Datatype && a.Language == b.Language && slices.Equal(a.Aliases, b.Aliases)
+}
@@ -0,0 +1,252 @@
+package kg
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	record := func(modify func(*KG) error) *Revision {
+		revision, err := kg.RecordChanges(modify)
+		assert.NoError(err)
+		return revision
+	}
+
+	// Revision 1
+	revision := record(func(g *KG) error {
+		if err := g.InsertTriple("Alice", "works_for", "Acme", true); err != nil {
+			return err
+		}
+		return g.Insert(Triple{Subject: "Alice", Predicate: "born", Object: "1990", ObjectType: DatatypeInteger}, true)
+	})
+	assert.Equal(1, revision.Number)
+	assert.Len(revision.Changes, 5, "Three nodes and two triples are inserted")
+	assert.Equal(`inserted entity Alice`, revision.Changes[0].String())
+	assert.Equal(`inserted (Alice, born, "1990"^^integer)`, revision.Changes[4].String())
+
+	// Revision 2
+	revision = record(func(g *KG) error {
+		return g.InsertTriple("Bob", "works_for", "Acme", true, "hr")
+	})
+	assert.Equal(2, revision.Number)
+	assert.Equal("inserted (Bob, works_for, Acme) in graph hr", revision.Changes[1].String())
+
+	// Unchanged graphs and failed changes
+	assert.Nil(record(func(g *KG) error { return nil }))
+	assert.Nil(record(func(g *KG) error { return g.InsertTriple("Bob", "works_for", "Acme", true, "hr") }))
+	_, err := kg.RecordChanges(func(g *KG) error {
+		_, err := g.RenameEntity("Nobody", "Somebody", true)
+		return err
+	})
+	assert.Error(err)
+
+	// Revision 3
+	revision = record(func(g *KG) error {
+		g.PruneOrphans = true
+		defer func() { g.PruneOrphans = false }()
+		if removed, err := g.RemoveTriple("Alice", "works_for", "Acme", true); err != nil || !removed {
+			t.Fatal("Failed to remove a triple")
+		}
+		_, err := g.RenameEntity("Acme", "Acme Corp", true)
+		return err
+	})
+	var descriptions []string
+	for _, change := range revision.Changes {
+		descriptions = append(descriptions, change.String())
+	}
+	assert.Equal([]string{"removed (Alice, works_for, Acme)", "removed entity Acme", "inserted entity Acme Corp"}, descriptions)
+	assert.Equal(3, kg.Revision())
+	assert.Len(kg.History(), 3)
+
+	// Time travel
+	past, err := kg.AtRevision(1)
+	assert.NoError(err)
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}}, past.FindTriples("", "works_for", "", true))
+	assert.Equal(1, past.Revision())
+	empty, err := kg.AtRevision(0)
+	assert.NoError(err)
+	assert.Empty(empty.ListNodes())
+	_, err = kg.AtRevision(4)
+	assert.Error(err)
+	assert.Equal([][3]string{{"Bob", "works_for", "Acme Corp"}}, kg.FindTriples("", "works_for", "", true), "Time travel leaves the graph unchanged")
+
+	// The history is saved with the graph
+	var buffer bytes.Buffer
+	assert.NoError(WriteTo(&buffer, kg))
+	kg, err = ReadFrom(&buffer)
+	assert.NoError(err)
+	assert.Equal(3, kg.Revision())
+
+	// Undo
+	undone, err := kg.UndoLastChange()
+	assert.NoError(err)
+	assert.Equal(3, undone.Number)
+	assert.Equal(2, kg.Revision())
+	assert.Len(kg.FindTriples("", "works_for", "Acme", true), 2)
+	assert.Nil(kg.FindNode("Acme Corp", true), "The indexes are restored")
+
+	// The undo is not recorded as a change, and the next change reuses the revision number
+	_, err = kg.RecordChanges(func(g *KG) error {
+		_, err := g.UndoLastChange()
+		return err
+	})
+	assert.NoError(err)
+	assert.Equal(1, kg.Revision())
+	revision = record(func(g *KG) error { return g.InsertTriple("Carol", "works_for", "Acme", true) })
+	assert.Equal(2, revision.Number)
+
+	_, err = kg.UndoLastChange()
+	assert.NoError(err)
+	_, err = kg.UndoLastChange()
+	assert.NoError(err)
+	assert.Empty(kg.ListNodes())
+	_, err = kg.UndoLastChange()
+	assert.Error(err)
+}
+
+func TestHistorySettings(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	_, err := kg.RecordChanges(func(g *KG) error { return g.InsertTriple("Alice", "parent_of", "Bob", true) })
+	assert.NoError(err)
+	revision, err := kg.RecordChanges(func(g *KG) error { return g.DeclareInverse("parent_of", "child_of") })
+	assert.NoError(err)
+	assert.Equal(2, revision.Number)
+	assert.Len(revision.Changes, 1)
+	assert.Equal(ChangeUpdate, revision.Changes[0].Kind)
+	assert.Equal("changed the declarations of child_of, parent_of", revision.Changes[0].String())
+	update := revision.Changes[0].Settings
+	assert.Nil(update.Schema, "Unchanged settings are not recorded")
+	assert.Nil(update.Rules, "Unchanged settings are not recorded")
+	assert.Equal([]DeclarationChange{
+		{Name: "child_of", After: &PredicateDeclaration{Name: "child_of", Inverse: "parent_of"}},
+		{Name: "parent_of", After: &PredicateDeclaration{Name: "parent_of", Inverse: "child_of"}},
+	}, update.Declarations)
+	assert.Equal("child_of", kg.inverseOf("parent_of"))
+
+	// Undoing the declaration keeps the triples
+	undone, err := kg.UndoLastChange()
+	assert.NoError(err)
+	assert.Equal(2, undone.Number)
+	assert.Empty(kg.sortedDeclarations())
+	assert.Equal("", kg.inverseOf("parent_of"))
+	assert.Equal([][3]string{{"Alice", "parent_of", "Bob"}}, kg.FindTriples("", "parent_of", "", true))
+
+	// Time travel restores the settings of the revision
+	_, err = kg.RecordChanges(func(g *KG) error {
+		return g.SetRules([]Rule{{
+			Name: "grandparent",
+			Body: []Atom{{Subject: "?x", Predicate: "parent_of", Object: "?y"}, {Subject: "?y", Predicate: "parent_of", Object: "?z"}},
+			Head: Atom{Subject: "?x", Predicate: "grandparent_of", Object: "?z"},
+		}})
+	})
+	assert.NoError(err)
+	assert.Len(kg.Rules(), 1)
+	past, err := kg.AtRevision(1)
+	assert.NoError(err)
+	assert.Empty(past.Rules())
+
+	// The changes of the settings are saved with the graph
+	assert.NoError(kg.DeclareSymmetric("married_to"))
+	_, err = kg.RecordChanges(func(g *KG) error { return g.RemoveDeclaration("married_to") })
+	assert.NoError(err)
+	var buffer bytes.Buffer
+	assert.NoError(WriteTo(&buffer, kg))
+	kg, err = ReadFrom(&buffer)
+	assert.NoError(err)
+	undone, err = kg.UndoLastChange()
+	assert.NoError(err)
+	assert.Equal([]DeclarationChange{{Name: "married_to", Before: &PredicateDeclaration{Name: "married_to", Symmetric: true}}}, undone.Changes[0].Settings.Declarations)
+	assert.Equal("married_to", kg.inverseOf("married_to"))
+	_, err = kg.UndoLastChange()
+	assert.NoError(err)
+	assert.Empty(kg.Rules())
+	assert.Equal("married_to", kg.inverseOf("married_to"), "Undoing the rules keeps the declarations")
+}
+
+func TestHistoryLimit(t *testing.T) {
+	kg := NewKG("sample")
+	kg.HistoryLimit = 2
+	assert := assert.New(t)
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		_, err := kg.RecordChanges(func(g *KG) error { return g.InsertTriple(name, "works_for", "Acme", true) })
+		assert.NoError(err)
+	}
+	assert.Equal(3, kg.Revision())
+	history := kg.History()
+	assert.Len(history, 2, "The oldest revision is forgotten")
+	assert.Equal(2, history[0].Number)
+	_, err := kg.AtRevision(0)
+	assert.Error(err)
+	past, err := kg.AtRevision(1)
+	assert.NoError(err)
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}}, past.FindTriples("", "works_for", "", true))
+
+	// Undo up to the oldest revision kept
+	for range 2 {
+		_, err = kg.UndoLastChange()
+		assert.NoError(err)
+	}
+	_, err = kg.UndoLastChange()
+	assert.Error(err)
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}}, kg.FindTriples("", "works_for", "", true))
+
+	// Without history
+	kg.HistoryLimit = -1
+	revision, err := kg.RecordChanges(func(g *KG) error { return g.InsertTriple("Dave", "works_for", "Acme", true) })
+	assert.NoError(err)
+	assert.Nil(revision)
+	assert.Empty(kg.History())
+}
+
+func TestConcurrentRecordChanges(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	// A modification recorded while another one is in progress waits for it
+	started, release := make(chan struct{}), make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := kg.RecordChanges(func(g *KG) error {
+			if err := g.InsertTriple("Alice", "works_for", "Acme", true); err != nil {
+				return err
+			}
+			close(started)
+			<-release
+			return nil
+		})
+		assert.NoError(err)
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		_, err := kg.RecordChanges(func(g *KG) error { return g.InsertTriple("Bob", "works_for", "Initech", true) })
+		assert.NoError(err)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Each revision holds the changes of its own modification only
+	var statements [][3]string
+	for _, revision := range kg.History() {
+		for _, change := range revision.Changes {
+			if change.Triple != nil {
+				statements = append(statements, change.Statement)
+			}
+		}
+		assert.Len(revision.Changes, 3, "Revision %d should hold two entities and a triple", revision.Number)
+	}
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}, {"Bob", "works_for", "Initech"}}, statements)
+}
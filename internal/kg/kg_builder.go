@@ -8,11 +8,13 @@ import "gonum.org/v1/gonum/graph"
 func (kg *KG) NewNode() graph.Node {
 	kg.mu.Lock()
 	defer kg.mu.Unlock()
-	
+
 	n := &Node{
 		Identifier: kg.currentID,
 	}
+	kg.recordNode(n)
 	kg.nodes[kg.currentID] = n
+	kg.deferIndexing(n)
 	kg.currentID++
 	return n
 }
@@ -22,7 +24,7 @@ func (kg *KG) NewNode() graph.Node {
 func (kg *KG) AddNode(n graph.Node) {
 	kg.mu.Lock()
 	defer kg.mu.Unlock()
-	
+
 	if _, exists := kg.nodes[n.ID()]; exists {
 		panic("graph: AddNode: node ID collision")
 	}
@@ -30,7 +32,9 @@ func (kg *KG) AddNode(n graph.Node) {
 	if !ok {
 		node = &Node{Identifier: n.ID()}
 	}
+	kg.recordNode(node)
 	kg.nodes[n.ID()] = node
+	kg.deferIndexing(node)
 }
 
 // NewEdge returns a new Edge (Predicate) from the source to the destination node.
@@ -51,31 +55,14 @@ func (kg *KG) NewEdge(from graph.Node, to graph.Node) graph.Edge {
 // implementation-dependent.
 // Whether e, e.From() and e.To() are stored
 // within the graph is implementation dependent.
+// As the graph is a multigraph, SetEdge replaces an existing predicate only
+// when it has the same subject; predicates with other subjects are kept.
 func (kg *KG) SetEdge(e graph.Edge) {
 	kg.mu.Lock()
 	defer kg.mu.Unlock()
-	
-	from := e.From()
-	to := e.To()
-
-	// Add nodes if they don't exist
-	if _, exists := kg.nodes[from.ID()]; !exists {
-		// We're already holding the lock, so don't call AddNode which would try to acquire it again
-		node, ok := from.(*Node)
-		if !ok {
-			node = &Node{Identifier: from.ID()}
-		}
-		kg.nodes[from.ID()] = node
-	}
 
-	if _, exists := kg.nodes[to.ID()]; !exists {
-		// We're already holding the lock, so don't call AddNode which would try to acquire it again
-		node, ok := to.(*Node)
-		if !ok {
-			node = &Node{Identifier: to.ID()}
-		}
-		kg.nodes[to.ID()] = node
-	}
+	from := kg.ensureNode(e.From())
+	to := kg.ensureNode(e.To())
 
 	// Create the predicate
 	pred, ok := e.(*Predicate)
@@ -86,15 +73,195 @@ func (kg *KG) SetEdge(e graph.Edge) {
 		}
 	}
 
+	// Replace an existing predicate with the same subject in the same graph, if any
+	lines := kg.from[from.ID()][to.ID()]
+	for i, existing := range lines {
+		if existing.Subject == pred.Subject && existing.Graph == pred.Graph {
+			pred.Identifier = existing.Identifier
+			kg.removePredicate(lines[i])
+			kg.addPredicate(pred)
+			return
+		}
+	}
+
+	pred.Identifier = kg.currentLineID
+	kg.currentLineID++
+	kg.addPredicate(pred)
+}
+
+// NewLine returns a new Line (Predicate) from the source to the destination node.
+// The returned Line has a graph-unique ID.
+// This method satisfies the graph.LineAdder interface.
+func (kg *KG) NewLine(from graph.Node, to graph.Node) graph.Line {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	pred := &Predicate{
+		F:          from,
+		T:          to,
+		Identifier: kg.currentLineID,
+	}
+	kg.currentLineID++
+	return pred
+}
+
+// SetLine adds a line from one node to another.
+// The nodes are added if they do not exist.
+// A line with the same ID between the same nodes is replaced.
+// This method satisfies the graph.LineAdder interface.
+func (kg *KG) SetLine(l graph.Line) {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	from := kg.ensureNode(l.From())
+	to := kg.ensureNode(l.To())
+
+	pred, ok := l.(*Predicate)
+	if !ok {
+		pred = &Predicate{
+			F:          from,
+			T:          to,
+			Identifier: l.ID(),
+		}
+	}
+
+	for _, existing := range kg.from[from.ID()][to.ID()] {
+		if existing.Identifier == pred.Identifier {
+			kg.removePredicate(existing)
+			break
+		}
+	}
+
+	if pred.Identifier >= kg.currentLineID {
+		kg.currentLineID = pred.Identifier + 1
+	}
+	kg.addPredicate(pred)
+}
+
+// newEntityNode creates and indexes a new entity node with the given lexical value and key.
+// A key is generated if none is given.
+// The caller must hold the write lock.
+func (kg *KG) newEntityNode(lexical, key string) *Node {
+	if key == "" {
+		key = generatedKey(kg.currentID)
+	}
+
+	// Create the node without calling kg.NewNode() to avoid lock reacquisition
+	node := &Node{
+		Identifier: kg.currentID,
+		Key:        key,
+		Lexical:    lexical,
+	}
+	kg.recordNode(node)
+	kg.nodes[kg.currentID] = node
+	kg.indexNode(node)
+	kg.currentID++
+	return node
+}
+
+// newLiteralNode creates and indexes a new node holding the literal value.
+// The caller must hold the write lock.
+func (kg *KG) newLiteralNode(literal Literal) *Node {
+	node := &Node{
+		Identifier: kg.currentID,
+		Lexical:    literal.Value,
+		Datatype:   literal.Datatype,
+		Language:   literal.Language,
+	}
+	kg.recordNode(node)
+	kg.nodes[kg.currentID] = node
+	kg.indexNode(node)
+	kg.currentID++
+	return node
+}
+
+// removeNode removes the node from the graph and its indexes.
+// The node must not have any predicate left.
+// The caller must hold the write lock.
+func (kg *KG) removeNode(node *Node) {
+	kg.recordNode(node)
+	kg.unindexNode(node)
+	delete(kg.nodes, node.ID())
+}
+
+// isOrphan reports whether the node has neither outgoing nor incoming predicates.
+// The caller must hold at least a read lock.
+func (kg *KG) isOrphan(node graph.Node) bool {
+	return len(kg.from[node.ID()]) == 0 && len(kg.to[node.ID()]) == 0
+}
+
+// ensureNode returns the node of the graph with the same ID as n.
+// The node is added to the graph if it does not exist.
+// The caller must hold the write lock.
+func (kg *KG) ensureNode(n graph.Node) graph.Node {
+	if existing, exists := kg.nodes[n.ID()]; exists {
+		return existing
+	}
+	node, ok := n.(*Node)
+	if !ok {
+		node = &Node{Identifier: n.ID()}
+	}
+	kg.recordNode(node)
+	kg.nodes[n.ID()] = node
+	kg.deferIndexing(node)
+	return node
+}
+
+// addPredicate stores the predicate in both adjacency maps.
+// The caller must hold the write lock.
+func (kg *KG) addPredicate(pred *Predicate) {
+	kg.recordTriple(pred)
+	fromID := pred.F.ID()
+	toID := pred.T.ID()
+
 	// Initialize maps if they don't exist
-	if kg.from[from.ID()] == nil {
-		kg.from[from.ID()] = make(map[int64]*Predicate)
+	if kg.from[fromID] == nil {
+		kg.from[fromID] = make(map[int64][]*Predicate)
 	}
-	if kg.to[to.ID()] == nil {
-		kg.to[to.ID()] = make(map[int64]*Predicate)
+	if kg.to[toID] == nil {
+		kg.to[toID] = make(map[int64][]*Predicate)
 	}
 
-	// Set the edge in both maps
-	kg.from[from.ID()][to.ID()] = pred
-	kg.to[to.ID()][from.ID()] = pred
+	kg.from[fromID][toID] = append(kg.from[fromID][toID], pred)
+	kg.to[toID][fromID] = append(kg.to[toID][fromID], pred)
+	kg.indexPredicate(pred)
+}
+
+// removePredicate removes the predicate from both adjacency maps and
+// cleans up the maps left empty.
+// The caller must hold the write lock.
+func (kg *KG) removePredicate(pred *Predicate) {
+	kg.recordTriple(pred)
+	fromID := pred.F.ID()
+	toID := pred.T.ID()
+
+	kg.from[fromID][toID] = withoutPredicate(kg.from[fromID][toID], pred)
+	if len(kg.from[fromID][toID]) == 0 {
+		delete(kg.from[fromID], toID)
+	}
+	if len(kg.from[fromID]) == 0 {
+		delete(kg.from, fromID)
+	}
+
+	kg.to[toID][fromID] = withoutPredicate(kg.to[toID][fromID], pred)
+	if len(kg.to[toID][fromID]) == 0 {
+		delete(kg.to[toID], fromID)
+	}
+	if len(kg.to[toID]) == 0 {
+		delete(kg.to, toID)
+	}
+
+	kg.unindexPredicate(pred)
+}
+
+// withoutPredicate returns a copy of lines without pred.
+// A copy is returned so that iterators handed out earlier are not affected.
+func withoutPredicate(lines []*Predicate, pred *Predicate) []*Predicate {
+	result := make([]*Predicate, 0, len(lines))
+	for _, line := range lines {
+		if line != pred {
+			result = append(result, line)
+		}
+	}
+	return result
 }
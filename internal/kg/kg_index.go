@@ -0,0 +1,297 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// foldKey returns the case-folded form of a lexical value used as key of the case-insensitive indexes.
+func foldKey(lexical string) string {
+	return strings.ToLower(lexical)
+}
+
+// addToIndex adds the node to the set stored under key, creating the index if needed.
+func addToIndex(index *map[string]map[int64]*Node, key string, node *Node) {
+	if *index == nil {
+		*index = make(map[string]map[int64]*Node)
+	}
+	if (*index)[key] == nil {
+		(*index)[key] = make(map[int64]*Node)
+	}
+	(*index)[key][node.ID()] = node
+}
+
+// removeFromIndex removes the node from the set stored under key and cleans up empty sets.
+func removeFromIndex(index map[string]map[int64]*Node, key string, node *Node) {
+	if index[key] == nil {
+		return
+	}
+	delete(index[key], node.ID())
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
+
+// indexNode adds the lexical value and the aliases of the node to the exact and case-folded lexical indexes.
+// Literal values are kept apart in the literal index.
+// The caller must hold the write lock.
+func (kg *KG) indexNode(node *Node) {
+	kg.invalidateInferred()
+	delete(kg.pending, node.ID())
+	if node.Lexical == "" {
+		return
+	}
+	if node.IsLiteral() {
+		addToIndex(&kg.literalIndex, foldKey(node.Lexical), node)
+		return
+	}
+	if node.Key != "" {
+		if kg.keyIndex == nil {
+			kg.keyIndex = make(map[string]*Node)
+		}
+		kg.keyIndex[node.Key] = node
+	}
+	for _, label := range append([]string{node.Lexical}, node.Aliases...) {
+		addToIndex(&kg.lexicalIndex, label, node)
+		addToIndex(&kg.foldedIndex, foldKey(label), node)
+	}
+}
+
+// unindexNode removes the lexical value and the aliases of the node from the lexical indexes.
+// It must be called before the labels of an indexed node are changed.
+// The caller must hold the write lock.
+func (kg *KG) unindexNode(node *Node) {
+	kg.invalidateInferred()
+	delete(kg.pending, node.ID())
+	if node.IsLiteral() {
+		removeFromIndex(kg.literalIndex, foldKey(node.Lexical), node)
+		return
+	}
+	if kg.keyIndex[node.Key] == node {
+		delete(kg.keyIndex, node.Key)
+	}
+	for _, label := range append([]string{node.Lexical}, node.Aliases...) {
+		removeFromIndex(kg.lexicalIndex, label, node)
+		removeFromIndex(kg.foldedIndex, foldKey(label), node)
+	}
+}
+
+// deferIndexing records a node added through the gonum graph API.
+// Such nodes are usually labeled by the caller after they have been added,
+// so they are indexed by the next read or mutation of the graph.
+// The caller must hold the write lock.
+func (kg *KG) deferIndexing(node *Node) {
+	if kg.pending == nil {
+		kg.pending = make(map[int64]*Node)
+	}
+	kg.pending[node.ID()] = node
+}
+
+// indexPending indexes the nodes added through the gonum graph API.
+// The caller must hold the write lock.
+func (kg *KG) indexPending() {
+	for _, node := range kg.pending {
+		kg.indexNode(node)
+	}
+}
+
+// readLock acquires a read lock on the graph. The nodes added through the gonum graph API
+// are indexed first, under the write lock, so that the lookups find them.
+func (kg *KG) readLock() {
+	for {
+		kg.mu.RLock()
+		if len(kg.pending) == 0 {
+			return
+		}
+		kg.mu.RUnlock()
+		kg.mu.Lock()
+		kg.indexPending()
+		kg.mu.Unlock()
+	}
+}
+
+// indexPredicate adds the predicate to the predicate label index.
+// The caller must hold the write lock.
+func (kg *KG) indexPredicate(pred *Predicate) {
+	kg.invalidateInferred()
+	if kg.predicateIndex == nil {
+		kg.predicateIndex = make(map[string]map[int64]*Predicate)
+	}
+	key := foldKey(pred.Subject)
+	if kg.predicateIndex[key] == nil {
+		kg.predicateIndex[key] = make(map[int64]*Predicate)
+	}
+	kg.predicateIndex[key][pred.ID()] = pred
+	pred.inverse = kg.inverseOf(pred.Subject)
+}
+
+// unindexPredicate removes the predicate from the predicate label index.
+// The caller must hold the write lock.
+func (kg *KG) unindexPredicate(pred *Predicate) {
+	kg.invalidateInferred()
+	key := foldKey(pred.Subject)
+	if kg.predicateIndex[key] == nil {
+		return
+	}
+	if kg.predicateIndex[key][pred.ID()] == pred {
+		delete(kg.predicateIndex[key], pred.ID())
+	}
+	if len(kg.predicateIndex[key]) == 0 {
+		delete(kg.predicateIndex, key)
+	}
+}
+
+// rebuildIndex recomputes all the indexes from the nodes and predicates of the graph.
+// The caller must hold the write lock or have exclusive access to the graph.
+func (kg *KG) rebuildIndex() {
+	kg.lexicalIndex = make(map[string]map[int64]*Node)
+	kg.foldedIndex = make(map[string]map[int64]*Node)
+	kg.literalIndex = make(map[string]map[int64]*Node)
+	kg.predicateIndex = make(map[string]map[int64]*Predicate)
+	kg.keyIndex = make(map[string]*Node)
+	kg.pending = make(map[int64]*Node)
+
+	for _, node := range kg.nodes {
+		if node != nil {
+			kg.indexNode(node)
+		}
+	}
+	for _, toMap := range kg.from {
+		for _, lines := range toMap {
+			for _, pred := range lines {
+				kg.indexPredicate(pred)
+			}
+		}
+	}
+}
+
+// lookupNodes returns all the entity nodes whose lexical value or one of whose aliases matches, sorted by ID.
+// A reference that is the key of an entity designates that entity only.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+// The caller must hold at least a read lock.
+func (kg *KG) lookupNodes(lexical string, caseSensitiveSearch bool) []*Node {
+	if lexical == "" {
+		return nil
+	}
+	if node := kg.keyIndex[lexical]; node != nil {
+		return []*Node{node}
+	}
+
+	var candidates map[int64]*Node
+	if caseSensitiveSearch {
+		candidates = kg.lexicalIndex[lexical]
+	} else {
+		candidates = kg.foldedIndex[foldKey(lexical)]
+	}
+
+	result := make([]*Node, 0, len(candidates))
+	for _, node := range candidates {
+		// Skip stale entries of nodes relabeled behind the graph's back
+		if node.matches(lexical, caseSensitiveSearch) {
+			result = append(result, node)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
+
+// lookupLiterals returns all the literal nodes whose value matches, sorted by ID.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+// The caller must hold at least a read lock.
+func (kg *KG) lookupLiterals(value string, caseSensitiveSearch bool) []*Node {
+	candidates := kg.literalIndex[foldKey(value)]
+	result := make([]*Node, 0, len(candidates))
+	for _, node := range candidates {
+		if predicateMatches(node.Lexical, value, caseSensitiveSearch) {
+			result = append(result, node)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
+
+// findNode returns the node with the lowest ID whose lexical value matches, or nil.
+// When no lexical value matches, the node with the lowest ID having a matching alias is returned.
+// The caller must hold at least a read lock.
+func (kg *KG) findNode(lexical string, caseSensitiveSearch bool) *Node {
+	nodes := kg.lookupNodes(lexical, caseSensitiveSearch)
+	if len(nodes) == 0 {
+		return nil
+	}
+	for _, node := range nodes {
+		if predicateMatches(node.Lexical, lexical, caseSensitiveSearch) {
+			return node
+		}
+	}
+	return nodes[0]
+}
+
+// AmbiguousEntityError is returned when a reference to an entity matches several entities.
+// The entities can be told apart by their keys.
+type AmbiguousEntityError struct {
+	Reference  string
+	Candidates []*Node
+}
+
+// Error lists the candidates with their keys.
+func (err *AmbiguousEntityError) Error() string {
+	candidates := make([]string, len(err.Candidates))
+	for i, node := range err.Candidates {
+		candidates[i] = fmt.Sprintf("%s (key %s)", node.Lexical, node.Key)
+	}
+	return fmt.Sprintf("entity %q is ambiguous, use the key of one of: %s", err.Reference, strings.Join(candidates, ", "))
+}
+
+// resolveEntity returns the entity designated by reference, which is either a key or a label.
+// Among several entities matching a label, an entity whose lexical value is exactly the reference is preferred;
+// if it cannot be chosen that way, an *AmbiguousEntityError is returned.
+// It returns nil and no error if no entity matches.
+// The caller must hold at least a read lock.
+func (kg *KG) resolveEntity(reference string, caseSensitiveSearch bool) (*Node, error) {
+	nodes := kg.lookupNodes(reference, caseSensitiveSearch)
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+
+	var exact []*Node
+	for _, node := range nodes {
+		if node.Lexical == reference {
+			exact = append(exact, node)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+	if len(exact) == 0 {
+		exact = nodes
+	}
+	return nil, &AmbiguousEntityError{Reference: reference, Candidates: exact}
+}
+
+// lookupPredicates returns all the predicates whose subject matches, sorted by ID.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+// The caller must hold at least a read lock.
+func (kg *KG) lookupPredicates(subject string, caseSensitiveSearch bool) []*Predicate {
+	candidates := kg.predicateIndex[foldKey(subject)]
+	result := make([]*Predicate, 0, len(candidates))
+	for _, pred := range candidates {
+		if predicateMatches(pred.Subject, subject, caseSensitiveSearch) {
+			result = append(result, pred)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
@@ -0,0 +1,78 @@
+package kg
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexMaintenance(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Go", "created_by", "Google", true))
+
+	// Both the exact and the case-folded indexes should resolve the nodes
+	assert.NotNil(kg.FindNode("Python", true))
+	assert.Nil(kg.FindNode("python", true), "Case-sensitive lookup should not match another case")
+	assert.NotNil(kg.FindNode("python", false))
+	assert.Len(kg.QueryByPredicate("CREATED_BY", false), 2)
+	assert.Nil(kg.QueryByPredicate("CREATED_BY", true))
+
+	// Removing a triple should update the predicate index
+	removed, err := kg.RemoveTriple("Go", "created_by", "Google", true)
+	assert.NoError(err)
+	assert.True(removed)
+	assert.Equal([][2]string{{"Python", "Guido"}}, kg.QueryByPredicate("created_by", true))
+	assert.Equal([][3]string{{"Python", "created_by", "Guido"}}, kg.FindTriples("", "created_by", "", true))
+
+	// Nodes added through the gonum API are labeled after their creation
+	node := kg.NewNode().(*Node)
+	node.Lexical = "Rob Pike"
+	assert.Equal(node.ID(), kg.FindNode("rob pike", false).ID(), "Pending nodes should be found")
+	assert.NoError(kg.InsertTriple("Go", "designed_by", "Rob Pike", true))
+	assert.Equal(node.ID(), kg.FindNode("Rob Pike", true).ID(), "InsertTriple should reuse the pending node")
+	assert.Empty(kg.pending, "InsertTriple should index the pending nodes")
+
+	// The indexes should be rebuilt after a round trip
+	var gobBuf bytes.Buffer
+	assert.NoError(WriteTo(&gobBuf, kg))
+	fromGob, err := ReadFrom(&gobBuf)
+	assert.NoError(err)
+	assert.NotNil(fromGob.FindNode("rob pike", false))
+	assert.Len(fromGob.QueryByPredicate("designed_by", true), 1)
+
+	var jsonBuf bytes.Buffer
+	assert.NoError(SaveToJSON(&jsonBuf, kg))
+	fromJSON, err := ReadFromJSON(&jsonBuf)
+	assert.NoError(err)
+	assert.NotNil(fromJSON.FindNode("GUIDO", false))
+	assert.Len(fromJSON.FindTriples("", "created_by", "", false), 1)
+}
+
+func TestDescribeEntityWithIndex(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Paris", "is_capital_of", "France", true))
+	assert.NoError(kg.InsertTriple("Eiffel Tower", "located_in", "Paris", true))
+	assert.NoError(kg.InsertTriple("Paris", "twinned_with", "Paris", true))
+
+	triples := kg.DescribeEntity("paris", false)
+	assert.ElementsMatch([][3]string{
+		{"Paris", "is_capital_of", "France"},
+		{"Eiffel Tower", "located_in", "Paris"},
+		{"Paris", "twinned_with", "Paris"},
+	}, triples, "Self-loops should be reported once")
+	assert.Empty(kg.DescribeEntity("London", false))
+}
+
+func BenchmarkInsertTriple(b *testing.B) {
+	kg := NewKG("sample")
+	for i := 0; i < b.N; i++ {
+		_ = kg.InsertTriple(fmt.Sprintf("entity %d", i), "related_to", fmt.Sprintf("entity %d", i+1), false)
+	}
+}
@@ -1,287 +1,515 @@
 package kg
 
-import "strings"
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Triple describes a fact to insert in the knowledge graph.
+// The subject and an entity object are designated by a key or a label.
+// The object is an entity unless ObjectType or Language is set,
+// in which case it is a typed literal value.
+type Triple struct {
+	Subject    string
+	Predicate  string
+	Object     string
+	SubjectKey string    // Optional key of the subject, created with the Subject label if it does not exist
+	ObjectKey  string    // Optional key of an entity object, created with the Object label if it does not exist
+	ObjectType Datatype  // Datatype of a literal object, empty for an entity
+	Language   string    // Language tag of a language-tagged string object
+	Graph      string    // Name of the graph holding the triple, DefaultGraph if empty
+	Source     string    // Optional origin of the fact, such as a document URI or a conversation id
+	Agent      string    // Optional name of the agent asserting the fact
+	Confidence float64   // Optional confidence in the fact between 0 and 1
+	ValidFrom  time.Time // Optional time from which the fact holds
+	ValidTo    time.Time // Optional time from which the fact no longer holds
+}
 
 // InsertTriple creates a new entry in the knowledge graph represented as a triple.
 // It checks if the subject and object nodes exist using FindNode. If they don't exist,
 // it creates new nodes for them. Then it creates a predicate connecting these nodes.
+// The triple is inserted in each of the named graphs, or in the default graph if none is given.
 // The caseSensitiveSearch parameter determines if node matching is case-sensitive.
-func (kg *KG) InsertTriple(subject, predicate, object string, caseSensitiveSearch bool) error {
+func (kg *KG) InsertTriple(subject, predicate, object string, caseSensitiveSearch bool, graphs ...string) error {
+	if len(graphs) == 0 {
+		graphs = []string{DefaultGraph}
+	}
+
+	for _, graph := range graphs {
+		err := kg.Insert(Triple{
+			Subject:   subject,
+			Predicate: predicate,
+			Object:    object,
+			Graph:     graph,
+		}, caseSensitiveSearch)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Insert creates a new entry in the knowledge graph from the triple.
+// The subject node, and the object node if the object is an entity, are looked up
+// and created if they don't exist. An entity given with a key is looked up by that key only,
+// and created with that key; otherwise its label is looked up, and a key is generated for a new entity.
+// It returns an *AmbiguousEntityError if a label matches several entities. A literal object is validated against its datatype
+// and stored in a node of its own. The predicate records the provenance given in the triple
+// and its creation time. Inserting a triple that already exists in the same graph only updates
+// the provenance fields that are set, unless the existing triple no longer holds and the triple
+// is given another validity period, in which case both are kept as the history of the fact;
+// the same triple may be stored in several graphs, nodes being shared between graphs.
+// A triple giving the subject more values than the declared cardinality of the predicate allows
+// is handled according to the conflict policy of the declaration; it returns a *CardinalityError
+// if the policy rejects it.
+// The caseSensitiveSearch parameter determines if node and predicate matching is case-sensitive.
+func (kg *KG) Insert(triple Triple, caseSensitiveSearch bool) error {
+	if err := validateConfidence(triple.Confidence); err != nil {
+		return err
+	}
+	if err := validateValidity(triple.ValidFrom, triple.ValidTo); err != nil {
+		return err
+	}
+
+	var literal *Literal
+	if triple.ObjectType != "" || triple.Language != "" {
+		datatype := triple.ObjectType
+		if datatype == "" {
+			datatype = DatatypeString
+		}
+		l, err := NewLiteral(triple.Object, datatype, triple.Language)
+		if err != nil {
+			return err
+		}
+		literal = &l
+	}
+
 	kg.mu.Lock()
 	defer kg.mu.Unlock()
-	
-	// Check if the nodes already exist
-	var subjectNode, objectNode *Node
-
-	// We need to search for nodes without holding locks since we already have a write lock
-	// Local implementation of FindNode to avoid lock reacquisition
-	findNode := func(lexical string) *Node {
-		if kg.nodes == nil || len(kg.nodes) == 0 {
-			return nil
+
+	return kg.insert(triple, literal, caseSensitiveSearch)
+}
+
+// insert creates a new entry in the knowledge graph from the triple, as Insert does,
+// whose object is the literal if it is not nil and an entity otherwise.
+// The caller must hold the write lock.
+func (kg *KG) insert(triple Triple, literal *Literal, caseSensitiveSearch bool) error {
+	now := time.Now()
+
+	// Index the nodes added through the gonum API before looking up the indexes
+	kg.indexPending()
+
+	// Reject the triples violating a strictly enforced schema
+	if kg.schema != nil && kg.schema.Enforcement == EnforcementStrict {
+		if err := kg.checkTriple(triple, caseSensitiveSearch); err != nil {
+			return err
 		}
+	}
 
-		for _, node := range kg.nodes {
-			if node == nil || node.Lexical == "" {
-				continue
-			}
+	// Get or create subject node
+	subjectNode, err := kg.lookupEntity(triple.Subject, triple.SubjectKey, caseSensitiveSearch)
+	if err != nil {
+		return err
+	}
+	subjectCreated := subjectNode == nil
+	if subjectCreated {
+		subjectNode = kg.newEntityNode(triple.Subject, triple.SubjectKey)
+	}
 
-			if caseSensitiveSearch {
-				if node.Lexical == lexical {
-					return node
-				}
-			} else {
-				if strings.EqualFold(node.Lexical, lexical) {
-					return node
+	var objectNode *Node
+	objectCreated := literal != nil
+	if literal != nil {
+		// Inserting a literal value that the subject already has only updates its provenance
+		for _, lines := range kg.from[subjectNode.ID()] {
+			for _, existing := range lines {
+				if existing.Graph == triple.Graph &&
+					predicateMatches(existing.Subject, triple.Predicate, caseSensitiveSearch) &&
+					literal.matchesNode(existing.T.(*Node)) && existing.Metadata.restates(triple) {
+					kg.recordTriple(existing)
+					existing.Metadata.update(triple, now)
+					existing.Rule = "" // An inferred triple that is asserted is no longer inferred
+					return nil
 				}
 			}
 		}
 
-		return nil
-	}
+		// Literals are never shared between triples
+		objectNode = kg.newLiteralNode(*literal)
+	} else {
+		// Get or create object node
+		objectNode, err = kg.lookupEntity(triple.Object, triple.ObjectKey, caseSensitiveSearch)
+		if err != nil {
+			// Do not leave behind a subject created for nothing
+			if subjectCreated {
+				kg.removeNode(subjectNode)
+			}
+			return err
+		}
+		if objectNode == nil {
+			objectNode = kg.newEntityNode(triple.Object, triple.ObjectKey)
+			objectCreated = true
+		}
 
-	// Get or create subject node
-	subjectNode = findNode(subject)
-	if subjectNode == nil {
-		// Create new subject node without calling kg.NewNode() to avoid lock reacquisition
-		subjectNode = &Node{
-			Identifier: kg.currentID,
-			Lexical:    subject,
+		// Inserting a triple that already exists only updates its provenance
+		for _, existing := range kg.from[subjectNode.ID()][objectNode.ID()] {
+			if existing.Graph == triple.Graph && predicateMatches(existing.Subject, triple.Predicate, caseSensitiveSearch) &&
+				existing.Metadata.restates(triple) {
+				kg.recordTriple(existing)
+				existing.Metadata.update(triple, now)
+				existing.Rule = "" // An inferred triple that is asserted is no longer inferred
+				return nil
+			}
 		}
-		kg.nodes[kg.currentID] = subjectNode
-		kg.currentID++
 	}
 
-	// Get or create object node
-	objectNode = findNode(object)
-	if objectNode == nil {
-		// Create new object node without calling kg.NewNode() to avoid lock reacquisition
-		objectNode = &Node{
-			Identifier: kg.currentID,
-			Lexical:    object,
+	// Enforce the cardinality declared for the predicate
+	replaced, err := kg.checkCardinality(subjectNode, objectNode, triple.Predicate, Metadata{ValidFrom: triple.ValidFrom, ValidTo: triple.ValidTo})
+	if err != nil {
+		// Do not leave behind nodes created for nothing
+		if objectCreated {
+			kg.removeNode(objectNode)
 		}
-		kg.nodes[kg.currentID] = objectNode
-		kg.currentID++
+		if subjectCreated {
+			kg.removeNode(subjectNode)
+		}
+		return err
 	}
 
 	// Create and set the predicate
 	pred := &Predicate{
-		F:       subjectNode,
-		T:       objectNode,
-		Subject: predicate,
+		F:          subjectNode,
+		T:          objectNode,
+		Subject:    triple.Predicate,
+		Identifier: kg.currentLineID,
+		Graph:      triple.Graph,
+		Metadata: Metadata{
+			Created:    now,
+			Modified:   now,
+			Source:     triple.Source,
+			Agent:      triple.Agent,
+			Confidence: triple.Confidence,
+			ValidFrom:  triple.ValidFrom,
+			ValidTo:    triple.ValidTo,
+		},
 	}
+	kg.currentLineID++
 
 	// Add the edge to the graph without calling kg.SetEdge() to avoid lock reacquisition
-	// Initialize maps if they don't exist
-	if kg.from[subjectNode.ID()] == nil {
-		kg.from[subjectNode.ID()] = make(map[int64]*Predicate)
-	}
-	if kg.to[objectNode.ID()] == nil {
-		kg.to[objectNode.ID()] = make(map[int64]*Predicate)
-	}
+	kg.addPredicate(pred)
 
-	// Set the edge in both maps
-	kg.from[subjectNode.ID()][objectNode.ID()] = pred
-	kg.to[objectNode.ID()][subjectNode.ID()] = pred
+	// Remove the values replaced by the new one once the subject holds it, so that it is not pruned
+	kg.removeTriples(replaced)
 
 	return nil
 }
 
-// FindNode retrieves a node from the knowledge graph by its lexical value.
-// It searches through all nodes and compares their Lexical field with the provided subject.
+// FindNode retrieves a node from the knowledge graph by its key or its lexical value.
+// It looks up the indexes of the graph and compares the Lexical field of the candidates with the provided subject.
 // The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+// When several nodes match, the one with the lowest ID is returned; use ResolveEntity
+// to detect ambiguous labels instead.
 // It returns nil if no matching node is found.
 func (kg *KG) FindNode(subject string, caseSensitiveSearch bool) *Node {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
-	
-	if kg.nodes == nil || len(kg.nodes) == 0 {
-		return nil
-	}
 
-	for _, node := range kg.nodes {
-		if node == nil || node.Lexical == "" {
-			continue
-		}
+	return kg.findNode(subject, caseSensitiveSearch)
+}
 
-		if caseSensitiveSearch {
-			if node.Lexical == subject {
-				return node
-			}
-		} else {
-			if strings.EqualFold(node.Lexical, subject) {
-				return node
-			}
-		}
-	}
+// ResolveEntity returns the entity designated by reference, which is either its key or one of its labels.
+// It returns an *AmbiguousEntityError listing the candidates if the reference matches several entities,
+// unless exactly one of them has the reference as lexical value.
+// It returns nil and no error if no entity matches.
+// The caseSensitiveSearch parameter determines if the comparison of labels is case-sensitive.
+func (kg *KG) ResolveEntity(reference string, caseSensitiveSearch bool) (*Node, error) {
+	kg.readLock()
+	defer kg.mu.RUnlock()
 
-	return nil
+	return kg.resolveEntity(reference, caseSensitiveSearch)
+}
+
+// lookupEntity returns the entity designated by key if a key is given, or by label otherwise.
+// It returns nil if the entity does not exist, after checking that the key can be used to create it.
+// The caller must hold at least a read lock.
+func (kg *KG) lookupEntity(label, key string, caseSensitiveSearch bool) (*Node, error) {
+	if key == "" {
+		return kg.resolveEntity(label, caseSensitiveSearch)
+	}
+	if node := kg.keyIndex[key]; node != nil {
+		return node, nil
+	}
+	return nil, validateKey(key)
 }
 
 // FindPredicate retrieves a predicate from the knowledge graph by its subject value.
-// It searches through all predicates in the graph and compares their subject field.
+// It looks up the predicate index of the graph and compares the subject field of the candidates.
 // The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
 // It returns nil if no matching predicate is found.
 func (kg *KG) FindPredicate(subject string, caseSensitiveSearch bool) *Predicate {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
-	
-	if kg.from == nil || len(kg.from) == 0 {
+
+	if subject == "" {
 		return nil
 	}
 
-	for _, toMap := range kg.from {
-		for _, pred := range toMap {
-			if pred == nil || pred.Subject == "" {
-				continue
-			}
-
-			if caseSensitiveSearch {
-				if pred.Subject == subject {
-					return pred
-				}
-			} else {
-				if strings.EqualFold(pred.Subject, subject) {
-					return pred
-				}
-			}
-		}
+	predicates := kg.lookupPredicates(subject, caseSensitiveSearch)
+	if len(predicates) == 0 {
+		return nil
 	}
 
-	return nil
+	return predicates[0]
 }
 
-// ListAllPredicates returns all unique predicate subjects in the knowledge graph.
+// ListAllPredicates returns all unique predicate subjects in the knowledge graph, sorted case-insensitively.
 // It returns an empty slice if there are no predicates in the graph.
 func (kg *KG) ListAllPredicates() []string {
-	kg.mu.RLock()
-	defer kg.mu.RUnlock()
-	
-	if kg.from == nil || len(kg.from) == 0 {
-		return []string{}
+	predicates, _, _ := kg.ListAllPredicatesPage(QueryOptions{})
+	return predicates
+}
+
+// ListAllPredicatesPage returns a page of the unique predicate subjects of the knowledge graph,
+// in the order given by the options, and the total number of predicates.
+// It returns an error if the options are invalid.
+func (kg *KG) ListAllPredicatesPage(options QueryOptions) ([]string, int, error) {
+	if err := options.Validate(); err != nil {
+		return nil, 0, err
 	}
 
-	predicates := make(map[string]struct{}) // Use a map to deduplicate predicate subjects
+	kg.readLock()
+	defer kg.mu.RUnlock()
 
-	for _, toMap := range kg.from {
-		for _, pred := range toMap {
-			if pred != nil && pred.Subject != "" {
-				predicates[pred.Subject] = struct{}{}
-			}
+	// Deduplicate the predicate subjects, keeping the first predicate of each
+	first := make(map[string]*Predicate)
+	for _, pred := range kg.allPredicates() {
+		if pred == nil || pred.Subject == "" {
+			continue
+		}
+		if existing := first[pred.Subject]; existing == nil || pred.Identifier < existing.Identifier {
+			first[pred.Subject] = pred
 		}
 	}
 
-	// Convert the map to a slice
-	result := make([]string, 0, len(predicates))
-	for pred := range predicates {
-		result = append(result, pred)
+	predicates := make([]*Predicate, 0, len(first))
+	for _, pred := range first {
+		predicates = append(predicates, pred)
 	}
+	sort.Slice(predicates, func(i, j int) bool {
+		return predicates[i].Identifier < predicates[j].Identifier
+	})
 
-	return result
+	result := make([]string, 0, len(predicates))
+	for _, pred := range predicates {
+		result = append(result, pred.Subject)
+	}
+	return pageLabels(result, options), len(result), nil
 }
 
-// ListNodes returns the lexical values of all nodes in the knowledge graph.
+// ListNodes returns the lexical values of all entity nodes in the knowledge graph, sorted case-insensitively.
+// Literal values are not listed.
 // It returns an empty slice if there are no nodes in the graph.
 func (kg *KG) ListNodes() []string {
-	kg.mu.RLock()
+	nodes, _, _ := kg.ListNodesPage(QueryOptions{})
+	return nodes
+}
+
+// ListNodesPage returns a page of the lexical values of the entity nodes of the knowledge graph,
+// in the order given by the options, and the total number of entities.
+// It returns an error if the options are invalid.
+func (kg *KG) ListNodesPage(options QueryOptions) ([]string, int, error) {
+	if err := options.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	kg.readLock()
 	defer kg.mu.RUnlock()
-	
-	if kg.nodes == nil || len(kg.nodes) == 0 {
-		return []string{}
+
+	entities := kg.entities()
+	result := make([]string, 0, len(entities))
+	for _, node := range entities {
+		result = append(result, node.Lexical)
 	}
+	return pageLabels(result, options), len(result), nil
+}
 
-	nodes := make([]string, 0, len(kg.nodes))
+// entities returns the entity nodes of the knowledge graph in the order of their creation.
+// The caller must hold at least a read lock.
+func (kg *KG) entities() []*Node {
+	entities := make([]*Node, 0, len(kg.nodes))
 	for _, node := range kg.nodes {
-		if node != nil && node.Lexical != "" {
-			nodes = append(nodes, node.Lexical)
+		if node != nil && node.Lexical != "" && !node.IsLiteral() {
+			entities = append(entities, node)
 		}
 	}
-
-	return nodes
+	// Nodes are created in the order of their IDs
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Identifier < entities[j].Identifier
+	})
+	return entities
 }
 
 // RemoveTriple removes a triple from the knowledge graph based on the provided subject, predicate, and object values.
+// The triple is removed from each of the named graphs, or from every graph holding it if none is given.
+// The subject and an entity object are designated by their key or a label; the object may also be a literal value.
+// The subject and object entities left without any predicate are deleted if PruneOrphans is set.
 // The caseSensitiveSearch parameter determines if the node and predicate matching is case-sensitive.
-// It returns true if the triple was found and successfully removed, false otherwise.
-func (kg *KG) RemoveTriple(subject, predicate, object string, caseSensitiveSearch bool) bool {
+// It returns true if the triple was found and successfully removed, false otherwise,
+// and an *AmbiguousEntityError if the subject or the object matches several entities, in which case nothing is removed.
+func (kg *KG) RemoveTriple(subject, predicate, object string, caseSensitiveSearch bool, graphs ...string) (bool, error) {
 	// Check for nil graph
 	if kg == nil {
-		return false
+		return false, nil
 	}
 
 	kg.mu.Lock()
 	defer kg.mu.Unlock()
-	
-	// Local implementation of FindNode to avoid lock reacquisition
-	findNode := func(lexical string) *Node {
-		if kg.nodes == nil || len(kg.nodes) == 0 {
-			return nil
+
+	// Find the subject node
+	subjectNode, err := kg.resolveEntity(subject, caseSensitiveSearch)
+	if err != nil || subjectNode == nil {
+		return false, err
+	}
+	objectEntity, err := kg.resolveEntity(object, caseSensitiveSearch)
+	if err != nil {
+		return false, err
+	}
+
+	// Look for the matching predicates whose object is either the entity or a literal value
+	var matches []*Predicate
+	for _, lines := range kg.from[subjectNode.ID()] {
+		for _, pred := range lines {
+			objectNode := pred.T.(*Node)
+			if inGraphs(pred.Graph, graphs) &&
+				predicateMatches(pred.Subject, predicate, caseSensitiveSearch) &&
+				(objectNode == objectEntity || (objectNode.IsLiteral() && objectNode.matches(object, caseSensitiveSearch))) {
+				matches = append(matches, pred)
+			}
 		}
+	}
+
+	kg.removeTriples(matches)
+
+	return len(matches) > 0, nil
+}
 
-		for _, node := range kg.nodes {
-			if node == nil || node.Lexical == "" {
+// removeTriples removes the predicates from the graph, with their literal objects.
+// If PruneOrphans is set, the entities left without any predicate are removed too.
+// The caller must hold the write lock.
+func (kg *KG) removeTriples(predicates []*Predicate) {
+	for _, pred := range predicates {
+		// Remove the predicate from both maps
+		kg.removePredicate(pred)
+	}
+
+	for _, pred := range predicates {
+		for _, node := range []*Node{pred.F.(*Node), pred.T.(*Node)} {
+			if _, exists := kg.nodes[node.ID()]; !exists || !kg.isOrphan(node) {
 				continue
 			}
-
-			if caseSensitiveSearch {
-				if node.Lexical == lexical {
-					return node
-				}
-			} else {
-				if strings.EqualFold(node.Lexical, lexical) {
-					return node
-				}
+			// A literal value belongs to the triple and goes away with it
+			if node.IsLiteral() || kg.PruneOrphans {
+				kg.removeNode(node)
 			}
 		}
-
-		return nil
 	}
+}
 
-	// Find the subject and object nodes
-	subjectNode := findNode(subject)
-	if subjectNode == nil {
-		return false
+// RemoveBySource removes every triple whose provenance source is exactly source,
+// in all graphs, and deletes the nodes left without any predicate by the removal.
+// Nodes that were already isolated are kept.
+// It returns the number of triples and the number of nodes removed.
+func (kg *KG) RemoveBySource(source string) (triples, nodes int) {
+	// Check for nil graph
+	if kg == nil || source == "" {
+		return 0, 0
 	}
 
-	objectNode := findNode(object)
-	if objectNode == nil {
-		return false
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	// Collect the predicates first so the maps are not modified while iterating
+	var matches []*Predicate
+	for _, pred := range kg.allPredicates() {
+		if pred.Metadata.Source == source {
+			matches = append(matches, pred)
+		}
 	}
 
-	// Check if a predicate exists between these nodes
-	if kg.from[subjectNode.ID()] == nil {
-		return false
+	touched := make(map[int64]*Node)
+	for _, pred := range matches {
+		kg.removePredicate(pred)
+		touched[pred.F.ID()] = pred.F.(*Node)
+		touched[pred.T.ID()] = pred.T.(*Node)
 	}
 
-	pred := kg.from[subjectNode.ID()][objectNode.ID()]
-	if pred == nil {
-		return false
+	// Delete the nodes orphaned by the removal, literal values included
+	for _, node := range touched {
+		if kg.isOrphan(node) {
+			kg.removeNode(node)
+			nodes++
+		}
 	}
 
-	// Check if the predicate matches
-	matches := false
-	if caseSensitiveSearch {
-		matches = pred.Subject == predicate
-	} else {
-		matches = strings.EqualFold(pred.Subject, predicate)
+	return len(matches), nodes
+}
+
+// ListGraphs returns the sorted names of the graphs holding at least one triple.
+// The default graph is listed as DefaultGraph.
+func (kg *KG) ListGraphs() []string {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	graphs := make(map[string]struct{}) // Use a map to deduplicate graph names
+	for _, pred := range kg.allPredicates() {
+		graphs[pred.Graph] = struct{}{}
 	}
 
-	if !matches {
-		return false
+	result := make([]string, 0, len(graphs))
+	for graph := range graphs {
+		result = append(result, graph)
 	}
+	sort.Strings(result)
 
-	// Remove the predicate from both maps
-	delete(kg.from[subjectNode.ID()], objectNode.ID())
-	delete(kg.to[objectNode.ID()], subjectNode.ID())
+	return result
+}
 
-	// If the subject node has no more outgoing edges, clean up the empty map
-	if len(kg.from[subjectNode.ID()]) == 0 {
-		delete(kg.from, subjectNode.ID())
+// inGraphs reports whether the graph name is one of the selected graphs.
+// An empty selection selects every graph. Graph names are case-sensitive.
+func inGraphs(graph string, graphs []string) bool {
+	if len(graphs) == 0 {
+		return true
 	}
+	return slices.Contains(graphs, graph)
+}
 
-	// If the object node has no more incoming edges, clean up the empty map
-	if len(kg.to[objectNode.ID()]) == 0 {
-		delete(kg.to, objectNode.ID())
+// predicateMatches reports whether the predicate subject value matches the searched one.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+func predicateMatches(value, subject string, caseSensitiveSearch bool) bool {
+	if caseSensitiveSearch {
+		return value == subject
 	}
+	return strings.EqualFold(value, subject)
+}
 
-	return true
+// predicateSelector returns a function reporting whether a triple is in one of the graphs
+// and has one of the predicates, every graph or predicate being selected if the list is empty.
+// The caseSensitiveSearch parameter determines if the predicates are compared case-sensitively.
+func predicateSelector(predicates, graphs []string, caseSensitiveSearch bool) func(*Predicate) bool {
+	return func(pred *Predicate) bool {
+		if !inGraphs(pred.Graph, graphs) {
+			return false
+		}
+		if len(predicates) == 0 {
+			return true
+		}
+		for _, predicate := range predicates {
+			if predicateMatches(pred.Subject, predicate, caseSensitiveSearch) {
+				return true
+			}
+		}
+		return false
+	}
 }
@@ -1,6 +1,7 @@
 package kg
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -210,3 +211,185 @@ func TestListNodes(t *testing.T) {
 	nodes = emptyKG.ListNodes()
 	assert.Empty(nodes, "Empty graph should return empty node list")
 }
+
+func TestInsertMultiplePredicatesBetweenSameNodes(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Python", "maintained_by", "Guido", true))
+
+	// Inserting the same triple again should not duplicate it
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido", true))
+
+	triples := kg.FindTriples("Python", "", "Guido", true)
+	assert.Len(triples, 2, "Both predicates should be kept between Python and Guido")
+	assert.ElementsMatch([][3]string{
+		{"Python", "created_by", "Guido"},
+		{"Python", "maintained_by", "Guido"},
+	}, triples)
+
+	// Removing one predicate keeps the other one
+	removed, err := kg.RemoveTriple("Python", "created_by", "Guido", true)
+	assert.NoError(err)
+	assert.True(removed)
+	removed, err = kg.RemoveTriple("Python", "created_by", "Guido", true)
+	assert.NoError(err)
+	assert.False(removed, "The triple should already be removed")
+	triples = kg.FindTriples("Python", "", "Guido", true)
+	assert.Equal([][3]string{{"Python", "maintained_by", "Guido"}}, triples)
+
+	python := kg.FindNode("Python", true)
+	guido := kg.FindNode("Guido", true)
+	assert.True(kg.HasEdgeFromTo(python.ID(), guido.ID()), "The remaining predicate should still link the nodes")
+
+	removed, err = kg.RemoveTriple("Python", "maintained_by", "Guido", true)
+	assert.NoError(err)
+	assert.True(removed)
+	assert.False(kg.HasEdgeFromTo(python.ID(), guido.ID()), "No predicate should link the nodes anymore")
+
+	// An ambiguous object removes nothing; its key designates a single triple
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "visited", Object: "Paris", ObjectKey: "geo:paris-fr"}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "visited", Object: "Paris", ObjectKey: "geo:paris-tx"}, true))
+	removed, err = kg.RemoveTriple("Alice", "visited", "Paris", true)
+	var ambiguous *AmbiguousEntityError
+	assert.ErrorAs(err, &ambiguous)
+	assert.False(removed)
+	assert.Len(kg.FindTriples("Alice", "visited", "", true), 2)
+	removed, err = kg.RemoveTriple("Alice", "visited", "geo:paris-tx", true)
+	assert.NoError(err)
+	assert.True(removed)
+	assert.Len(kg.FindTriples("Alice", "visited", "", true), 1)
+}
+
+func TestNamedGraphs(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Python", "used_by", "Alice", true))
+	assert.NoError(kg.InsertTriple("Python", "used_by", "Bob", true, "project-a"))
+	assert.NoError(kg.InsertTriple("Python", "used_by", "Carol", true, "project-a", "project-b"))
+
+	// The same triple is kept once per graph
+	assert.NoError(kg.InsertTriple("Python", "used_by", "Carol", true, "project-b"))
+	assert.Len(kg.FindTriples("Python", "used_by", "Carol", true), 2, "The triple should be stored in both graphs")
+	assert.Equal([]string{DefaultGraph, "project-a", "project-b"}, kg.ListGraphs())
+
+	// Nodes are shared between graphs
+	assert.Len(kg.ListNodes(), 4)
+
+	// Queries cover all graphs unless a subset is selected
+	assert.Len(kg.FindTriples("Python", "", "", true), 4)
+	assert.ElementsMatch([][3]string{
+		{"Python", "used_by", "Bob"},
+		{"Python", "used_by", "Carol"},
+	}, kg.FindTriples("Python", "", "", true, "project-a"))
+	assert.Equal([][3]string{{"Python", "used_by", "Alice"}}, kg.FindTriples("Python", "", "", true, DefaultGraph))
+	assert.Len(kg.DescribeEntity("Carol", true, "project-b"), 1)
+	assert.Empty(kg.DescribeEntity("Alice", true, "project-a", "project-b"))
+
+	predicates, err := kg.MatchTriples(TriplePattern{Object: "Bob", Graphs: []string{"project-a"}}, true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal("project-a", predicates[0].Graph)
+
+	// Removing from one graph keeps the triple in the others
+	removed, err := kg.RemoveTriple("Python", "used_by", "Carol", true, "project-a")
+	assert.NoError(err)
+	assert.True(removed)
+	removed, err = kg.RemoveTriple("Python", "used_by", "Carol", true, "project-a")
+	assert.NoError(err)
+	assert.False(removed, "The triple should already be removed from project-a")
+	assert.Equal([][3]string{{"Python", "used_by", "Carol"}}, kg.FindTriples("", "", "Carol", true, "project-b"))
+
+	// Removing without graph names removes the triple from every graph
+	assert.NoError(kg.InsertTriple("Python", "used_by", "Carol", true))
+	removed, err = kg.RemoveTriple("Python", "used_by", "Carol", true)
+	assert.NoError(err)
+	assert.True(removed)
+	assert.Empty(kg.FindTriples("", "", "Carol", true))
+}
+
+func TestRemoveBySource(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "created_by", Object: "Guido", Source: "doc-1"}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger, Source: "doc-1"}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "is_a", Object: "Language", Source: "doc-2"}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Guido", Predicate: "born_in", Object: "Haarlem", Source: "doc-1", Graph: "people"}, true))
+	isolated := kg.NewNode().(*Node)
+	isolated.Lexical = "Isolated"
+
+	triples, nodes := kg.RemoveBySource("doc-1")
+	assert.Equal(3, triples, "The triples of doc-1 should be removed from every graph")
+	assert.Equal(3, nodes, "Guido, Haarlem and the literal value should be removed")
+
+	assert.Equal([][3]string{{"Python", "is_a", "Language"}}, kg.FindTriples("", "", "", true))
+	assert.Nil(kg.FindNode("Guido", true))
+	assert.Nil(kg.FindNode("Haarlem", true))
+	assert.NotNil(kg.FindNode("Python", true), "Python is still used by doc-2")
+	assert.NotNil(kg.FindNode("Isolated", true), "Nodes that were already isolated should be kept")
+
+	triples, nodes = kg.RemoveBySource("doc-1")
+	assert.Zero(triples)
+	assert.Zero(nodes)
+}
+
+func TestEntityKeys(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Paris", "capital_of", "France", true))
+	assert.NoError(kg.Insert(Triple{Subject: "Paris", SubjectKey: "geo:paris-tx", Predicate: "located_in", Object: "Texas"}, true))
+
+	paris := kg.FindNode("France", true)
+	assert.NotNil(paris)
+	assert.Equal("_:e1", paris.Key, "A key should be generated for entities inserted without one")
+	parisTX := kg.FindNode("geo:paris-tx", true)
+	assert.NotNil(parisTX)
+	assert.Equal("Paris", parisTX.Lexical)
+	assert.Len(kg.ListNodes(), 4, "Both entities named Paris should be kept apart")
+
+	// An ambiguous label is reported with its candidates
+	_, err := kg.ResolveEntity("Paris", true)
+	var ambiguous *AmbiguousEntityError
+	assert.ErrorAs(err, &ambiguous)
+	assert.Len(ambiguous.Candidates, 2)
+	assert.Contains(err.Error(), "geo:paris-tx")
+	assert.ErrorAs(kg.InsertTriple("Paris", "twinned_with", "Rome", true), &ambiguous)
+	assert.Nil(kg.FindNode("Rome", true), "A failed insertion should not create nodes")
+
+	// Keys designate a single entity everywhere
+	parisFR, err := kg.ResolveEntity("_:e0", true)
+	assert.NoError(err)
+	assert.Equal("Paris", parisFR.Lexical)
+	assert.NoError(kg.InsertTriple("_:e0", "twinned_with", "Rome", true))
+	assert.Equal([][3]string{{"Paris", "located_in", "Texas"}}, kg.DescribeEntity("geo:paris-tx", true))
+	assert.Len(kg.FindTriples("_:e0", "", "", true), 2)
+	assert.Len(kg.FindTriples("Paris", "", "", true), 3, "Pattern queries match every entity with the label")
+
+	// Queries about a single entity report an ambiguous label instead of choosing one of the entities
+	_, err = kg.QueryBySubject("Paris", true)
+	assert.ErrorAs(err, &ambiguous)
+	_, err = kg.PredicatesFromTo("Paris", "France", true)
+	assert.ErrorAs(err, &ambiguous)
+	predicates, err := kg.PredicatesFromTo("geo:paris-tx", "Texas", true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	results, err := kg.QueryByObject("Texas", true)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"located_in": {"Paris"}}, results)
+
+	// Existing keys are reused and reserved keys are rejected
+	assert.NoError(kg.Insert(Triple{Subject: "Paris, TX", SubjectKey: "geo:paris-tx", Predicate: "population", Object: "24171", ObjectType: DatatypeInteger}, true))
+	assert.Len(kg.DescribeEntity("geo:paris-tx", true), 2)
+	assert.Error(kg.Insert(Triple{Subject: "Lyon", SubjectKey: "_:e42", Predicate: "located_in", Object: "France"}, true))
+
+	// Keys survive serialization
+	var buf bytes.Buffer
+	assert.NoError(SaveToJSON(&buf, kg))
+	restored, err := ReadFromJSON(&buf)
+	assert.NoError(err)
+	assert.Len(restored.DescribeEntity("geo:paris-tx", true), 2)
+}
@@ -0,0 +1,445 @@
+package kg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// MergeEntities merges the entity drop into the entity keep.
+// Every predicate of the dropped node, outgoing or incoming, is moved onto the kept node.
+// A moved predicate that duplicates a predicate of the kept node in the same graph
+// is discarded; the existing predicate and its metadata are kept.
+// The lexical value, the aliases and a user-supplied key of the dropped node become aliases of the kept node,
+// so that later lookups of the dropped label resolve to the kept node.
+// It returns an *AmbiguousEntityError if a label matches several entities.
+// The caseSensitiveSearch parameter determines if the entity lookups are case-sensitive.
+// It returns the number of predicates moved and the number of duplicates discarded.
+func (kg *KG) MergeEntities(keep, drop string, caseSensitiveSearch bool) (moved, duplicates int, err error) {
+	// Check for nil graph
+	if kg == nil {
+		return 0, 0, fmt.Errorf("entity %q not found", keep)
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.indexPending()
+
+	keepNode, err := kg.resolveEntity(keep, caseSensitiveSearch)
+	if err != nil {
+		return 0, 0, err
+	}
+	if keepNode == nil {
+		return 0, 0, fmt.Errorf("entity %q not found", keep)
+	}
+	dropNode, err := kg.resolveEntity(drop, caseSensitiveSearch)
+	if err != nil {
+		return 0, 0, err
+	}
+	if dropNode == nil {
+		return 0, 0, fmt.Errorf("entity %q not found", drop)
+	}
+	if keepNode == dropNode {
+		return 0, 0, fmt.Errorf("%q and %q are the same entity", keep, drop)
+	}
+
+	moved, duplicates = kg.mergeNodes(keepNode, dropNode, true)
+	return moved, duplicates, nil
+}
+
+// mergeNodes moves every predicate of dropNode onto keepNode, discarding duplicates,
+// and removes dropNode. Its aliases and user-supplied key become aliases of keepNode,
+// as well as its lexical value if aliasLexical is true.
+// It returns the number of predicates moved and the number of duplicates discarded.
+// The caller must hold the write lock.
+func (kg *KG) mergeNodes(keepNode, dropNode *Node, aliasLexical bool) (moved, duplicates int) {
+	for _, pred := range kg.incidentPredicates(dropNode) {
+		kg.removePredicate(pred)
+		if pred.F.ID() == dropNode.ID() {
+			pred.F = keepNode
+		}
+		if pred.T.ID() == dropNode.ID() {
+			pred.T = keepNode
+		}
+
+		if kg.duplicatePredicate(pred) != nil {
+			// A literal value belongs to the discarded triple and goes away with it
+			if objectNode := pred.T.(*Node); objectNode.IsLiteral() {
+				kg.removeNode(objectNode)
+			}
+			duplicates++
+			continue
+		}
+
+		kg.addPredicate(pred)
+		moved++
+	}
+
+	// Record the labels of the dropped node as aliases of the kept node
+	kg.removeNode(dropNode)
+	kg.recordNode(keepNode)
+	kg.unindexNode(keepNode)
+	labels := append([]string(nil), dropNode.Aliases...)
+	if aliasLexical {
+		labels = append([]string{dropNode.Lexical}, labels...)
+	}
+	if !strings.HasPrefix(dropNode.Key, generatedKeyPrefix) {
+		labels = append(labels, dropNode.Key)
+	}
+	for _, label := range labels {
+		if label != "" && !keepNode.matches(label, true) {
+			keepNode.Aliases = append(keepNode.Aliases, label)
+		}
+	}
+	kg.indexNode(keepNode)
+
+	return moved, duplicates
+}
+
+// incidentPredicates returns the outgoing and incoming predicates of the node.
+// Self-loops are returned once. The result can be used while the maps are modified.
+// The caller must hold at least a read lock.
+func (kg *KG) incidentPredicates(node graph.Node) []*Predicate {
+	seen := make(map[int64]bool)
+	var predicates []*Predicate
+	for _, adjacency := range []map[int64][]*Predicate{kg.from[node.ID()], kg.to[node.ID()]} {
+		for _, lines := range adjacency {
+			for _, pred := range lines {
+				if !seen[pred.ID()] {
+					seen[pred.ID()] = true
+					predicates = append(predicates, pred)
+				}
+			}
+		}
+	}
+	return predicates
+}
+
+// duplicatePredicate returns a predicate of the graph stating the same triple as pred
+// in the same graph, or nil. Predicates are compared by subject and linked nodes;
+// a literal object is compared by value since literal nodes are never shared.
+// The caller must hold at least a read lock.
+func (kg *KG) duplicatePredicate(pred *Predicate) *Predicate {
+	objectNode := pred.T.(*Node)
+	if !objectNode.IsLiteral() {
+		for _, existing := range kg.from[pred.F.ID()][pred.T.ID()] {
+			if existing != pred && existing.Subject == pred.Subject && existing.Graph == pred.Graph {
+				return existing
+			}
+		}
+		return nil
+	}
+
+	literal := Literal{Value: objectNode.Lexical, Datatype: objectNode.Datatype, Language: objectNode.Language}
+	for _, lines := range kg.from[pred.F.ID()] {
+		for _, existing := range lines {
+			if existing != pred && existing.Subject == pred.Subject && existing.Graph == pred.Graph &&
+				literal.matchesNode(existing.T.(*Node)) {
+				return existing
+			}
+		}
+	}
+	return nil
+}
+
+// AddAlias records alias as an alternate label of the entity, so that lookups of the alias
+// resolve to the entity. Adding a label the entity already has is a no-op.
+// It returns an error if the entity does not exist or is ambiguous, or if another entity already uses the label.
+// The caseSensitiveSearch parameter determines if the lookups are case-sensitive.
+func (kg *KG) AddAlias(entity, alias string, caseSensitiveSearch bool) error {
+	if alias == "" {
+		return fmt.Errorf("empty alias for entity %q", entity)
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.indexPending()
+
+	node, err := kg.resolveEntity(entity, caseSensitiveSearch)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("entity %q not found", entity)
+	}
+	for _, other := range kg.lookupNodes(alias, caseSensitiveSearch) {
+		if other != node {
+			return fmt.Errorf("alias %q is already a label of entity %q", alias, other.Lexical)
+		}
+	}
+	if node.matches(alias, true) {
+		return nil
+	}
+
+	kg.recordNode(node)
+	kg.unindexNode(node)
+	node.Aliases = append(node.Aliases, alias)
+	kg.indexNode(node)
+
+	return nil
+}
+
+// RemoveAlias removes alias from the alternate labels of the entity.
+// The entity may be designated by any of its labels, including the alias being removed.
+// It returns an error if the entity does not exist or is ambiguous, or if it does not have the alias.
+// The caseSensitiveSearch parameter determines if the lookups are case-sensitive.
+func (kg *KG) RemoveAlias(entity, alias string, caseSensitiveSearch bool) error {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.indexPending()
+
+	node, err := kg.resolveEntity(entity, caseSensitiveSearch)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("entity %q not found", entity)
+	}
+
+	aliases := make([]string, 0, len(node.Aliases))
+	for _, existing := range node.Aliases {
+		if !predicateMatches(existing, alias, caseSensitiveSearch) {
+			aliases = append(aliases, existing)
+		}
+	}
+	if len(aliases) == len(node.Aliases) {
+		return fmt.Errorf("entity %q has no alias %q", node.Lexical, alias)
+	}
+
+	kg.recordNode(node)
+	kg.unindexNode(node)
+	node.Aliases = aliases
+	if len(node.Aliases) == 0 {
+		node.Aliases = nil
+	}
+	kg.indexNode(node)
+
+	return nil
+}
+
+// RenameEntity changes the label of the entity oldName to newName.
+// The entity keeps its key and aliases; the old label is not kept as an alias.
+// If newName already designates another entity, the renamed entity is merged into it as by MergeEntities,
+// except that the old label does not become an alias.
+// The caseSensitiveSearch parameter determines if the entity lookups are case-sensitive.
+// It returns the number of triples changed, and an error if oldName does not exist or
+// if oldName or newName is ambiguous.
+func (kg *KG) RenameEntity(oldName, newName string, caseSensitiveSearch bool) (int, error) {
+	if newName == "" {
+		return 0, fmt.Errorf("empty new name for entity %q", oldName)
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.indexPending()
+
+	node, err := kg.resolveEntity(oldName, caseSensitiveSearch)
+	if err != nil {
+		return 0, err
+	}
+	if node == nil {
+		return 0, fmt.Errorf("entity %q not found", oldName)
+	}
+	target, err := kg.resolveEntity(newName, caseSensitiveSearch)
+	if err != nil {
+		return 0, err
+	}
+
+	// Merge on conflict with another entity
+	if target != nil && target != node {
+		moved, duplicates := kg.mergeNodes(target, node, false)
+		return moved + duplicates, nil
+	}
+
+	kg.recordNode(node)
+	kg.unindexNode(node)
+	node.Lexical = newName
+	aliases := make([]string, 0, len(node.Aliases))
+	for _, alias := range node.Aliases {
+		if alias != newName {
+			aliases = append(aliases, alias)
+		}
+	}
+	node.Aliases = aliases
+	if len(node.Aliases) == 0 {
+		node.Aliases = nil
+	}
+	kg.indexNode(node)
+
+	return len(kg.incidentPredicates(node)), nil
+}
+
+// RenamePredicate changes the subject of every predicate matching oldName to newName, in all graphs.
+// A renamed predicate that duplicates an existing triple with the new name is removed,
+// the existing triple and its metadata being kept.
+// The declarations of oldName, such as its inverse or its cardinality, and its definition in the schema
+// move to newName, merged with those of newName if it has any, and the rules using oldName use newName instead.
+// The caseSensitiveSearch parameter determines if the predicate matching is case-sensitive.
+// It returns the number of triples changed, and an error if no predicate matches oldName
+// or if the declarations or the definitions of both predicates conflict, in which case nothing is renamed.
+func (kg *KG) RenamePredicate(oldName, newName string, caseSensitiveSearch bool) (int, error) {
+	if newName == "" {
+		return 0, fmt.Errorf("empty new name for predicate %q", oldName)
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	predicates := kg.lookupPredicates(oldName, caseSensitiveSearch)
+	if len(predicates) == 0 {
+		return 0, fmt.Errorf("predicate %q not found", oldName)
+	}
+
+	renamed, err := kg.renamePredicateSettings(oldName, newName)
+	if err != nil {
+		return 0, err
+	}
+	kg.renamePredicateRules(oldName, newName, caseSensitiveSearch)
+
+	now := time.Now()
+	for _, pred := range predicates {
+		kg.recordTriple(pred)
+		kg.unindexPredicate(pred)
+		pred.Subject = newName
+		pred.Metadata.Modified = now
+
+		// Merge on conflict with an existing triple
+		if kg.duplicatePredicate(pred) != nil {
+			kg.removePredicate(pred)
+			if objectNode := pred.T.(*Node); objectNode.IsLiteral() {
+				kg.removeNode(objectNode)
+			}
+			continue
+		}
+
+		kg.indexPredicate(pred)
+	}
+	kg.refreshInverses(renamed...)
+
+	return len(predicates), nil
+}
+
+// renamePredicateSettings moves the declarations of the predicate oldName and its definition in the schema
+// to newName, merging them with those of newName.
+// It returns the names of the predicates whose declared inverse may have changed,
+// and an error if the declarations or the definitions conflict, in which case the settings are left unchanged.
+// The caller must hold the write lock.
+func (kg *KG) renamePredicateSettings(oldName, newName string) ([]string, error) {
+	// Merge the declarations first, so that nothing changes on conflict
+	declaration := kg.declarations[foldKey(oldName)]
+	var merged PredicateDeclaration
+	if declaration != nil {
+		merged = *declaration
+		if existing := kg.declarations[foldKey(newName)]; existing != nil && existing != declaration {
+			var err error
+			if merged, err = mergeDeclarations(*existing, *declaration); err != nil {
+				return nil, fmt.Errorf("cannot rename predicate %q to %q: %w", oldName, newName, err)
+			}
+		}
+		merged.Name = newName
+		if merged.Inverse != "" && (foldKey(merged.Inverse) == foldKey(newName) || foldKey(merged.Inverse) == foldKey(oldName)) {
+			return nil, fmt.Errorf("cannot rename predicate %q to %q: %q would be its own inverse", oldName, newName, newName)
+		}
+	}
+	schema, err := kg.schema.renamePredicate(oldName, newName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot rename predicate %q to %q: %w", oldName, newName, err)
+	}
+	kg.schema = schema
+
+	if declaration == nil {
+		return nil, nil
+	}
+	delete(kg.declarations, foldKey(oldName))
+	*kg.declaration(newName) = merged
+	names := []string{oldName, newName}
+	if merged.Inverse != "" {
+		kg.declaration(merged.Inverse).Inverse = newName
+		names = append(names, merged.Inverse)
+	}
+	return names, nil
+}
+
+// RemoveEntity deletes the entity designated by name and all the predicates linking it
+// to other nodes, in all graphs, along with its literal values.
+// The neighbors left without any predicate are deleted if PruneOrphans is set.
+// The caseSensitiveSearch parameter determines if the entity lookup is case-sensitive.
+// It returns the number of triples removed, and an error if the entity does not exist or is ambiguous.
+func (kg *KG) RemoveEntity(name string, caseSensitiveSearch bool) (int, error) {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.indexPending()
+
+	node, err := kg.resolveEntity(name, caseSensitiveSearch)
+	if err != nil {
+		return 0, err
+	}
+	if node == nil {
+		return 0, fmt.Errorf("entity %q not found", name)
+	}
+
+	predicates := kg.incidentPredicates(node)
+	kg.removeTriples(predicates)
+	if _, exists := kg.nodes[node.ID()]; exists {
+		kg.removeNode(node)
+	}
+
+	return len(predicates), nil
+}
+
+// CloseTriple ends the validity of the matching triples at validTo instead of removing them,
+// so that they remain as the history of the facts they state. A zero validTo ends it now.
+// Only the asserted triples that still hold are closed. An empty object matches every value of the predicate,
+// so that the current value of a subject can be closed before stating a new one.
+// The triples are searched in the named graphs if any are given, in all graphs otherwise.
+// The caseSensitiveSearch parameter determines if the matching is case-sensitive.
+// It returns the number of triples closed, and an error if the subject does not exist or is ambiguous,
+// or if validTo is not after the start of the validity of a matching triple.
+func (kg *KG) CloseTriple(subject, predicate, object string, validTo time.Time, caseSensitiveSearch bool, graphs ...string) (int, error) {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.indexPending()
+
+	subjectNode, err := kg.resolveEntity(subject, caseSensitiveSearch)
+	if err != nil {
+		return 0, err
+	}
+	if subjectNode == nil {
+		return 0, fmt.Errorf("entity %q not found", subject)
+	}
+
+	now := time.Now()
+	if validTo.IsZero() {
+		validTo = now
+	}
+
+	var closed []*Predicate
+	for _, lines := range kg.from[subjectNode.ID()] {
+		for _, pred := range lines {
+			if pred.Inferred() || !pred.Metadata.ValidTo.IsZero() || !inGraphs(pred.Graph, graphs) ||
+				!predicateMatches(pred.Subject, predicate, caseSensitiveSearch) ||
+				(object != "" && !pred.T.(*Node).matches(object, caseSensitiveSearch)) {
+				continue
+			}
+			if err := validateValidity(pred.Metadata.ValidFrom, validTo); err != nil {
+				return 0, fmt.Errorf("cannot close (%s, %s, %s): %w", subjectNode.Lexical, pred.Subject, pred.T.(*Node), err)
+			}
+			closed = append(closed, pred)
+		}
+	}
+
+	for _, pred := range closed {
+		kg.recordTriple(pred)
+		pred.Metadata.ValidTo = validTo
+		pred.Metadata.Modified = now
+	}
+	return len(closed), nil
+}
@@ -0,0 +1,226 @@
+package kg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeEntities(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido van Rossum", true))
+	assert.NoError(kg.InsertTriple("Python", "created_by", "G. van Rossum", true))
+	assert.NoError(kg.InsertTriple("G. van Rossum", "born_in", "Haarlem", true))
+	assert.NoError(kg.InsertTriple("G. van Rossum", "knows", "G. van Rossum", true))
+	assert.NoError(kg.Insert(Triple{Subject: "Guido van Rossum", Predicate: "born", Object: "1956-01-31", ObjectType: DatatypeDate}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "G. van Rossum", Predicate: "born", Object: "1956-01-31", ObjectType: DatatypeDate}, true))
+
+	moved, duplicates, err := kg.MergeEntities("Guido van Rossum", "G. van Rossum", true)
+	assert.NoError(err)
+	assert.Equal(2, moved, "born_in and the self-loop should be moved")
+	assert.Equal(2, duplicates, "created_by and the birth date are already known")
+
+	assert.ElementsMatch([][3]string{
+		{"Python", "created_by", "Guido van Rossum"},
+		{"Guido van Rossum", "born_in", "Haarlem"},
+		{"Guido van Rossum", "knows", "Guido van Rossum"},
+		{"Guido van Rossum", "born", "1956-01-31"},
+	}, kg.DescribeEntity("Guido van Rossum", true))
+	assert.Len(kg.ListNodes(), 3, "The dropped node should be removed")
+
+	// The dropped label resolves to the kept node
+	guido := kg.FindNode("g. van rossum", false)
+	assert.NotNil(guido)
+	assert.Equal("Guido van Rossum", guido.Lexical)
+	assert.Equal([]string{"G. van Rossum"}, guido.Aliases)
+	assert.Len(kg.FindTriples("G. van Rossum", "born_in", "", true), 1)
+
+	assert.NoError(kg.InsertTriple("G. van Rossum", "worked_at", "Google", true))
+	assert.Len(kg.ListNodes(), 4, "Inserting with the alias should not create a new node")
+
+	_, _, err = kg.MergeEntities("Guido van Rossum", "G. van Rossum", true)
+	assert.Error(err, "Merging an entity with itself should fail")
+	_, _, err = kg.MergeEntities("Guido van Rossum", "Unknown", true)
+	assert.Error(err)
+}
+
+func TestAliases(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Go", "created_by", "Google", true))
+	assert.NoError(kg.InsertTriple("Rust", "created_by", "Mozilla", true))
+
+	assert.NoError(kg.AddAlias("Go", "Golang", true))
+	assert.NoError(kg.AddAlias("golang", "Go", false), "Adding an existing label should be a no-op")
+	assert.Error(kg.AddAlias("Rust", "golang", false), "The alias is already used by Go")
+	assert.Error(kg.AddAlias("Python", "CPython", true), "Python does not exist")
+
+	// Every lookup resolves the alias to the canonical node
+	golang := kg.FindNode("GOLANG", false)
+	assert.NotNil(golang)
+	assert.Equal("Go", golang.Lexical)
+	assert.Equal([]string{"Golang"}, golang.Aliases)
+
+	assert.NoError(kg.InsertTriple("Golang", "has_mascot", "Gopher", true))
+	assert.Len(kg.ListNodes(), 5, "Inserting with the alias should not create a new node")
+	assert.ElementsMatch([][3]string{
+		{"Go", "created_by", "Google"},
+		{"Go", "has_mascot", "Gopher"},
+	}, kg.DescribeEntity("Golang", true))
+	predicates, err := kg.PredicatesFromTo("Golang", "Google", true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	removed, err := kg.RemoveTriple("Golang", "has_mascot", "Gopher", true)
+	assert.NoError(err)
+	assert.True(removed)
+
+	// The aliases survive serialization
+	for _, roundTrip := range []func(*KG) (*KG, error){
+		func(g *KG) (*KG, error) {
+			var buf bytes.Buffer
+			if err := WriteTo(&buf, g); err != nil {
+				return nil, err
+			}
+			return ReadFrom(&buf)
+		},
+		func(g *KG) (*KG, error) {
+			var buf bytes.Buffer
+			if err := SaveToJSON(&buf, g); err != nil {
+				return nil, err
+			}
+			return ReadFromJSON(&buf)
+		},
+	} {
+		restored, err := roundTrip(kg)
+		assert.NoError(err)
+		node := restored.FindNode("Golang", true)
+		assert.NotNil(node)
+		assert.Equal("Go", node.Lexical)
+	}
+
+	assert.Error(kg.RemoveAlias("Go", "Gopher", true), "Gopher is not an alias of Go")
+	assert.NoError(kg.RemoveAlias("Go", "golang", false))
+	assert.Nil(kg.FindNode("Golang", true))
+	assert.Empty(kg.FindNode("Go", true).Aliases)
+}
+
+func TestRenameEntity(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Pyhton", "created_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Django", "written_in", "Pyhton", true))
+
+	changed, err := kg.RenameEntity("Pyhton", "Python", true)
+	assert.NoError(err)
+	assert.Equal(2, changed)
+	assert.Nil(kg.FindNode("Pyhton", true), "The old label should not be kept")
+	python := kg.FindNode("Python", true)
+	assert.NotNil(python)
+	assert.Equal("_:e0", python.Key, "The entity should keep its key")
+	assert.ElementsMatch([][3]string{
+		{"Python", "created_by", "Guido"},
+		{"Django", "written_in", "Python"},
+	}, kg.DescribeEntity("Python", true))
+
+	// Renaming to the name of another entity merges them
+	assert.NoError(kg.InsertTriple("Guido van Rossum", "born_in", "Haarlem", true))
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido van Rossum", true))
+	changed, err = kg.RenameEntity("Guido", "Guido van Rossum", true)
+	assert.NoError(err)
+	assert.Equal(1, changed)
+	assert.Nil(kg.FindNode("Guido", true))
+	assert.Equal([][3]string{{"Python", "created_by", "Guido van Rossum"}}, kg.FindTriples("", "created_by", "", true))
+
+	_, err = kg.RenameEntity("Unknown", "Known", true)
+	assert.Error(err)
+}
+
+func TestRenamePredicate(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Python", "creatd_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Go", "creatd_by", "Google", true))
+	assert.NoError(kg.InsertTriple("Go", "created_by", "Google", true))
+
+	changed, err := kg.RenamePredicate("creatd_by", "created_by", true)
+	assert.NoError(err)
+	assert.Equal(2, changed)
+	assert.Nil(kg.FindPredicate("creatd_by", true))
+	assert.ElementsMatch([][3]string{
+		{"Python", "created_by", "Guido"},
+		{"Go", "created_by", "Google"},
+	}, kg.FindTriples("", "created_by", "", true), "The duplicate triple of Go should be merged")
+
+	_, err = kg.RenamePredicate("creatd_by", "created_by", true)
+	assert.Error(err, "The old predicate should not exist anymore")
+}
+
+func TestRenamePredicateDeclarations(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Alice", "parnt_of", "Bob", true))
+	assert.NoError(kg.InsertTriple("Carol", "parent_of", "Dave", true))
+	assert.NoError(kg.DeclareInverse("parnt_of", "child_of"))
+	assert.NoError(kg.DeclareFunctional("parent_of", ConflictFlag))
+	assert.NoError(kg.SetSchema(&Schema{Predicates: []PredicateDefinition{
+		{Name: "parnt_of", Description: "Parenthood"},
+		{Name: "parent_of"},
+	}}))
+
+	// The declarations and definitions of the old name are merged into those of the new one
+	_, err := kg.RenamePredicate("parnt_of", "parent_of", true)
+	assert.NoError(err)
+	assert.Nil(kg.Declaration("parnt_of"))
+	assert.Equal(&PredicateDeclaration{Name: "parent_of", Inverse: "child_of", MaxValues: 1, OnConflict: ConflictFlag}, kg.Declaration("parent_of"))
+	assert.Equal("parent_of", kg.Declaration("child_of").Inverse)
+	assert.Contains(kg.DescribeEntity("Dave", true), [3]string{"Dave", "child_of", "Carol"}, "The triples with the new name get its inverse")
+	assert.Equal([]PredicateDefinition{{Name: "parent_of", Description: "Parenthood"}}, kg.Schema().Predicates)
+
+	// Conflicting declarations leave the graph unchanged
+	assert.NoError(kg.InsertTriple("Alice", "mother_of", "Bob", true))
+	assert.NoError(kg.DeclareInverse("mother_of", "has_mother"))
+	_, err = kg.RenamePredicate("mother_of", "parent_of", true)
+	assert.Error(err)
+	assert.Len(kg.FindTriples("", "mother_of", "", true), 1)
+	assert.Equal("has_mother", kg.Declaration("mother_of").Inverse)
+}
+
+func TestRemoveEntity(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Django", "written_in", "Python", true))
+	assert.NoError(kg.InsertTriple("Django", "is_a", "Framework", true))
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, true))
+
+	removed, err := kg.RemoveEntity("python", false)
+	assert.NoError(err)
+	assert.Equal(3, removed)
+	assert.Nil(kg.FindNode("Python", true))
+	assert.Equal([][3]string{{"Django", "is_a", "Framework"}}, kg.FindTriples("", "", "", true))
+	assert.NotNil(kg.FindNode("Guido", true), "Orphans are kept unless PruneOrphans is set")
+	assert.Len(kg.ListNodes(), 3)
+
+	// With PruneOrphans, the entities left without facts are deleted
+	kg.PruneOrphans = true
+	assert.NoError(kg.InsertTriple("Go", "created_by", "Google", true))
+	removed, err = kg.RemoveEntity("Django", true)
+	assert.NoError(err)
+	assert.Equal(1, removed)
+	assert.Nil(kg.FindNode("Framework", true))
+	tripleRemoved, err := kg.RemoveTriple("Go", "created_by", "Google", true)
+	assert.NoError(err)
+	assert.True(tripleRemoved)
+	assert.ElementsMatch([]string{"Guido"}, kg.ListNodes(), "Only the entity orphaned before PruneOrphans was set should remain")
+
+	_, err = kg.RemoveEntity("Django", true)
+	assert.Error(err)
+}
@@ -1,215 +1,242 @@
 package kg
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // ListPredicatesFromNode returns all predicates originating from the node identified by subject.
 // The caseSensitiveSearch parameter determines if the node lookup is case-sensitive.
 // If the node is not found, it returns nil.
 // If the node has no outgoing predicates, it returns an empty slice.
-func (kg *KG) ListPredicatesFromNode(subject string, caseSensitiveSearch bool) []*Predicate {
+// It returns an *AmbiguousEntityError if the subject matches several entities.
+func (kg *KG) ListPredicatesFromNode(subject string, caseSensitiveSearch bool) ([]*Predicate, error) {
 	// Check for nil graph
 	if kg == nil {
-		return nil
+		return nil, nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
 	// Find the node
-	node := kg.FindNode(subject, caseSensitiveSearch)
+	node, err := kg.resolveEntity(subject, caseSensitiveSearch)
 	if node == nil {
-		return nil
+		return nil, err
 	}
 
 	// Check if there are outgoing edges
 	fromEdges := kg.from[node.ID()]
 	if fromEdges == nil || len(fromEdges) == 0 {
-		return []*Predicate{} // Return empty array instead of nil if node has no outgoing edges
+		return []*Predicate{}, nil // Return empty array instead of nil if node has no outgoing edges
 	}
 
 	// Collect all predicates
 	predicates := make([]*Predicate, 0, len(fromEdges))
-	for _, pred := range fromEdges {
-		if pred != nil {
-			predicates = append(predicates, pred)
+	for _, lines := range fromEdges {
+		for _, pred := range lines {
+			if pred != nil {
+				predicates = append(predicates, pred)
+			}
 		}
 	}
 
-	return predicates
+	return predicates, nil
 }
 
 // ListPredicatesToNode returns all predicates pointing to the node identified by subject.
 // The caseSensitiveSearch parameter determines if the node lookup is case-sensitive.
 // If the node is not found, it returns nil.
 // If the node has no incoming predicates, it returns an empty slice.
-func (kg *KG) ListPredicatesToNode(subject string, caseSensitiveSearch bool) []*Predicate {
+// It returns an *AmbiguousEntityError if the subject matches several entities.
+func (kg *KG) ListPredicatesToNode(subject string, caseSensitiveSearch bool) ([]*Predicate, error) {
 	// Check for nil graph
 	if kg == nil {
-		return nil
+		return nil, nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
 	// Find the node
-	node := kg.FindNode(subject, caseSensitiveSearch)
+	node, err := kg.resolveEntity(subject, caseSensitiveSearch)
 	if node == nil {
-		return nil
+		return nil, err
 	}
 
 	// Check if there are incoming edges
 	toEdges := kg.to[node.ID()]
 	if toEdges == nil || len(toEdges) == 0 {
-		return []*Predicate{} // Return empty array instead of nil if node has no incoming edges
+		return []*Predicate{}, nil // Return empty array instead of nil if node has no incoming edges
 	}
 
 	// Collect all predicates
 	predicates := make([]*Predicate, 0, len(toEdges))
-	for _, pred := range toEdges {
-		if pred != nil {
-			predicates = append(predicates, pred)
+	for _, lines := range toEdges {
+		for _, pred := range lines {
+			if pred != nil {
+				predicates = append(predicates, pred)
+			}
 		}
 	}
 
-	return predicates
+	return predicates, nil
 }
 
 // PredicatesFromTo returns all the predicates that link the node identified by fromSubject
-// to the node identified by toSubject.
+// to the node identified by toSubject, which may be an entity or a literal value.
+// The predicates linking toSubject to fromSubject that have a declared inverse are returned reversed,
+// labeled with their inverse; these reversed predicates do not belong to the graph.
 // The caseSensitiveSearch parameter determines if the node lookup is case-sensitive.
 // Returns nil if no link is found or if fromSubject or toSubject do not exist.
-func (kg *KG) PredicatesFromTo(fromSubject, toSubject string, caseSensitiveSearch bool) []*Predicate {
+// It returns an *AmbiguousEntityError if fromSubject or toSubject matches several entities.
+func (kg *KG) PredicatesFromTo(fromSubject, toSubject string, caseSensitiveSearch bool) ([]*Predicate, error) {
 	// Check for nil graph
 	if kg == nil {
-		return nil
+		return nil, nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
 	// Find the nodes
-	fromNode := kg.FindNode(fromSubject, caseSensitiveSearch)
+	fromNode, err := kg.resolveEntity(fromSubject, caseSensitiveSearch)
 	if fromNode == nil {
-		return nil
+		return nil, err
 	}
-
-	toNode := kg.FindNode(toSubject, caseSensitiveSearch)
-	if toNode == nil {
-		return nil
+	toNode, err := kg.resolveEntity(toSubject, caseSensitiveSearch)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if there are edges from fromNode
+	// Get the predicates from fromNode to toNode, stored or implied by an inverse declaration
 	fromEdges := kg.from[fromNode.ID()]
-	if fromEdges == nil {
-		return nil
+	var result []*Predicate
+	if toNode != nil {
+		result = append(result, fromEdges[toNode.ID()]...)
+		result = append(result, kg.impliedInverses(kg.from[toNode.ID()][fromNode.ID()])...)
 	}
 
-	// Get the predicate from fromNode to toNode
-	pred := fromEdges[toNode.ID()]
-	if pred == nil {
-		return nil
+	// The target may also be a literal value of fromNode
+	for toID, lines := range fromEdges {
+		if literal := kg.nodes[toID]; literal != nil && literal.IsLiteral() &&
+			predicateMatches(literal.Lexical, toSubject, caseSensitiveSearch) {
+			result = append(result, lines...)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, nil
 	}
 
-	// We need to return it as a slice since the function signature requires []*Predicate
-	return []*Predicate{pred}
+	return result, nil
 }
 
 // QueryBySubject returns all predicates and objects for a given subject.
 // The result is a map where keys are predicate subjects and values are arrays of object lexical values.
+// A triple pointing to the subject whose predicate has a declared inverse is included as its inverse.
 // The caseSensitiveSearch parameter determines if the node lookup is case-sensitive.
-// It returns nil if the subject is not found.
+// It returns nil if the subject is not found, and an *AmbiguousEntityError if it matches several entities.
 // If the subject exists but has no outgoing connections, it returns an empty map.
-func (kg *KG) QueryBySubject(subject string, caseSensitiveSearch bool) map[string][]string {
+func (kg *KG) QueryBySubject(subject string, caseSensitiveSearch bool) (map[string][]string, error) {
 	// Check for nil graph
 	if kg == nil {
-		return nil
+		return nil, nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	subjectNode := kg.FindNode(subject, caseSensitiveSearch)
+	subjectNode, err := kg.resolveEntity(subject, caseSensitiveSearch)
 	if subjectNode == nil {
-		return nil
+		return nil, err
 	}
 
-	// Get all edges starting from this node
+	// Get all edges starting from this node, including the inverses of the edges ending at it
 	result := make(map[string][]string)
-	fromEdges := kg.from[subjectNode.ID()]
-	if fromEdges == nil {
-		return result // Return empty map instead of nil if node exists but has no outgoing edges
+	fromEdges := make([][]*Predicate, 0, len(kg.from[subjectNode.ID()])+1)
+	for _, lines := range kg.from[subjectNode.ID()] {
+		fromEdges = append(fromEdges, lines)
 	}
+	fromEdges = append(fromEdges, kg.impliedInverses(adjacentPredicates(kg.to[subjectNode.ID()])))
 
 	// Process all outgoing edges
-	for _, pred := range fromEdges {
-		if pred == nil || pred.Subject == "" {
-			continue
-		}
+	for _, lines := range fromEdges {
+		for _, pred := range lines {
+			if pred == nil || pred.Subject == "" {
+				continue
+			}
 
-		toNode := pred.T.(*Node)
-		if toNode == nil || toNode.Lexical == "" {
-			continue
-		}
+			toNode := pred.T.(*Node)
+			if toNode == nil || toNode.Lexical == "" {
+				continue
+			}
 
-		// Initialize the slice if needed
-		if result[pred.Subject] == nil {
-			result[pred.Subject] = make([]string, 0)
-		}
+			// Initialize the slice if needed
+			if result[pred.Subject] == nil {
+				result[pred.Subject] = make([]string, 0)
+			}
 
-		// Add the object to the list
-		result[pred.Subject] = append(result[pred.Subject], toNode.Lexical)
+			// Add the object to the list
+			result[pred.Subject] = append(result[pred.Subject], toNode.Lexical)
+		}
 	}
 
-	return result
+	return result, nil
 }
 
 // QueryByObject returns all subjects and predicates pointing to a given object.
 // The result is a map where keys are predicate subjects and values are arrays of subject lexical values.
+// A triple starting from the object whose predicate has a declared inverse is included as its inverse.
 // The caseSensitiveSearch parameter determines if the node lookup is case-sensitive.
-// It returns nil if the object is not found.
+// It returns nil if the object is not found, and an *AmbiguousEntityError if it matches several entities.
 // If the object exists but has no incoming connections, it returns an empty map.
-func (kg *KG) QueryByObject(object string, caseSensitiveSearch bool) map[string][]string {
+func (kg *KG) QueryByObject(object string, caseSensitiveSearch bool) (map[string][]string, error) {
 	// Check for nil graph
 	if kg == nil {
-		return nil
+		return nil, nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	objectNode := kg.FindNode(object, caseSensitiveSearch)
+	objectNode, err := kg.resolveEntity(object, caseSensitiveSearch)
 	if objectNode == nil {
-		return nil
+		return nil, err
 	}
 
-	// Get all edges ending at this node
+	// Get all edges ending at this node, including the inverses of the edges starting from it
 	result := make(map[string][]string)
-	toEdges := kg.to[objectNode.ID()]
-	if toEdges == nil {
-		return result // Return empty map instead of nil if node exists but has no incoming edges
+	toEdges := make([][]*Predicate, 0, len(kg.to[objectNode.ID()])+1)
+	for _, lines := range kg.to[objectNode.ID()] {
+		toEdges = append(toEdges, lines)
 	}
+	toEdges = append(toEdges, kg.impliedInverses(adjacentPredicates(kg.from[objectNode.ID()])))
 
 	// Process all incoming edges
-	for _, pred := range toEdges {
-		if pred == nil || pred.Subject == "" {
-			continue
-		}
+	for _, lines := range toEdges {
+		for _, pred := range lines {
+			if pred == nil || pred.Subject == "" {
+				continue
+			}
 
-		fromNode := pred.F.(*Node)
-		if fromNode == nil || fromNode.Lexical == "" {
-			continue
-		}
+			fromNode := pred.F.(*Node)
+			if fromNode == nil || fromNode.Lexical == "" {
+				continue
+			}
 
-		// Initialize the slice if needed
-		if result[pred.Subject] == nil {
-			result[pred.Subject] = make([]string, 0)
-		}
+			// Initialize the slice if needed
+			if result[pred.Subject] == nil {
+				result[pred.Subject] = make([]string, 0)
+			}
 
-		// Add the subject to the list
-		result[pred.Subject] = append(result[pred.Subject], fromNode.Lexical)
+			// Add the subject to the list
+			result[pred.Subject] = append(result[pred.Subject], fromNode.Lexical)
+		}
 	}
 
-	return result
+	return result, nil
 }
 
 // QueryByPredicate returns all subjects and objects connected by a given predicate.
@@ -222,167 +249,331 @@ func (kg *KG) QueryByPredicate(predicate string, caseSensitiveSearch bool) [][2]
 		return nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	var result [][2]string
-
-	// Check if there are any edges
-	if kg.from == nil || len(kg.from) == 0 {
+	// Look up the predicates in the index instead of walking every edge
+	predicates := kg.lookupPredicates(predicate, caseSensitiveSearch)
+	if len(predicates) == 0 {
 		return nil
 	}
 
-	foundPredicate := false
-	// Iterate through all edges
-	for _, toMap := range kg.from {
-		for _, pred := range toMap {
-			if pred == nil || pred.Subject == "" {
-				continue
-			}
-
-			// Check if this is the predicate we're looking for
-			matches := false
-			if caseSensitiveSearch {
-				matches = pred.Subject == predicate
-			} else {
-				matches = strings.EqualFold(pred.Subject, predicate)
-			}
-
-			if matches {
-				foundPredicate = true
-				fromNode := pred.F.(*Node)
-				toNode := pred.T.(*Node)
+	var result [][2]string
+	for _, pred := range predicates {
+		fromNode := pred.F.(*Node)
+		toNode := pred.T.(*Node)
 
-				if fromNode != nil && toNode != nil && fromNode.Lexical != "" && toNode.Lexical != "" {
-					// Add the subject-object pair to the result
-					result = append(result, [2]string{fromNode.Lexical, toNode.Lexical})
-				}
-			}
+		if fromNode != nil && toNode != nil && fromNode.Lexical != "" && toNode.Lexical != "" {
+			// Add the subject-object pair to the result
+			result = append(result, [2]string{fromNode.Lexical, toNode.Lexical})
 		}
 	}
 
-	if !foundPredicate {
-		return nil
-	}
-
 	return result
 }
 
+// TriplePattern selects triples of the knowledge graph.
+// Any of Subject, Predicate and Object can be empty, which means "match any value".
+type TriplePattern struct {
+	Subject   string
+	Predicate string
+	Object    string
+	Filter    *Filter  // Optional condition on the object of the triples
+	Graphs    []string // Names of the graphs to search, all graphs if empty
+	// IncludeInferred selects the triples inferred by the rules of the graph, both the materialized ones
+	// and those evaluated at query time; only asserted triples are selected otherwise.
+	IncludeInferred bool
+	// AsOf selects the triples whose fact holds at that time, as given by their validity;
+	// a zero AsOf selects triples whatever their validity.
+	AsOf time.Time
+}
+
 // FindTriples returns all triples in the knowledge graph that match the given pattern.
-// The result is an array of [subject, predicate, object] triples, each represented as a 3-element string array.
+// The result is an array of [subject, predicate, object] triples, each represented as a 3-element string array,
+// sorted by subject, predicate and object.
 // Any of the parameters (subject, predicate, object) can be empty, which means "match any value".
+// The search is restricted to the named graphs if any are given; it covers all graphs otherwise.
+// Only asserted triples are returned; see MatchTriples for inferred triples.
 // The caseSensitiveSearch parameter determines if string matching is case-sensitive.
 // It returns an empty array if no matching triples are found.
-func (kg *KG) FindTriples(subject, predicate, object string, caseSensitiveSearch bool) [][3]string {
+func (kg *KG) FindTriples(subject, predicate, object string, caseSensitiveSearch bool, graphs ...string) [][3]string {
+	return kg.FindTriplesAsOf(subject, predicate, object, time.Time{}, caseSensitiveSearch, graphs...)
+}
+
+// FindTriplesAsOf returns the triples matching the given pattern, as FindTriples does,
+// whose fact holds at the time asOf. A zero asOf returns the triples whatever their validity.
+func (kg *KG) FindTriplesAsOf(subject, predicate, object string, asOf time.Time, caseSensitiveSearch bool, graphs ...string) [][3]string {
 	// Check for nil graph
 	if kg == nil {
 		return nil
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
+	// Matching can only fail on a filter
+	predicates, _ := kg.matchTriples(TriplePattern{
+		Subject:   subject,
+		Predicate: predicate,
+		Object:    object,
+		Graphs:    graphs,
+		AsOf:      asOf,
+	}, caseSensitiveSearch)
+
 	var result [][3]string
+	for _, pred := range pageTriples(predicates, QueryOptions{}) {
+		// Add the matching triple to the result
+		result = appendTriple(result, pred)
+	}
 
-	// If no edges exist, return empty result
-	if kg.from == nil || len(kg.from) == 0 {
-		return result
+	return result
+}
+
+// MatchTriples returns the predicates of all the triples that match the pattern,
+// sorted by subject, predicate and object.
+// The subject, predicate and object of each triple are given by the From, Subject and To fields of the predicates.
+// Inferred triples that are not materialized are returned as predicates that do not belong to the graph.
+// The caseSensitiveSearch parameter determines if string matching is case-sensitive.
+// It returns an error wrapping ErrTypeMismatch if the filter of the pattern
+// cannot be applied to the object of a candidate triple whose predicate is the one of the pattern.
+// Without a predicate in the pattern, the candidates whose object has a type the filter cannot be applied to
+// are skipped, and the error is only returned if none of the literal objects has a type it can be applied to.
+func (kg *KG) MatchTriples(pattern TriplePattern, caseSensitiveSearch bool) ([]*Predicate, error) {
+	predicates, _, err := kg.QueryTriples(pattern, QueryOptions{}, caseSensitiveSearch)
+	return predicates, err
+}
+
+// QueryTriples returns a page of the predicates of the triples that match the pattern, as MatchTriples does,
+// in the order given by the options, and the total number of matching triples.
+// It returns an error if the options are invalid or if the filter of the pattern cannot be applied.
+func (kg *KG) QueryTriples(pattern TriplePattern, options QueryOptions, caseSensitiveSearch bool) ([]*Predicate, int, error) {
+	// Check for nil graph
+	if kg == nil {
+		return nil, 0, nil
+	}
+	if err := options.Validate(); err != nil {
+		return nil, 0, err
 	}
 
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	predicates, err := kg.matchTriples(pattern, caseSensitiveSearch)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pageTriples(predicates, options), len(predicates), nil
+}
+
+// matchTriples returns the predicates of all the triples that match the pattern.
+// The caller must hold at least a read lock.
+func (kg *KG) matchTriples(pattern TriplePattern, caseSensitiveSearch bool) ([]*Predicate, error) {
+	var result []*Predicate
+	var mismatch error  // First literal whose type does not fit the filter
+	comparable := false // Whether a literal has a type that fits the filter
+
 	// Helper function to check if a string matches a pattern (empty pattern matches anything)
 	matchesPattern := func(value, pattern string) bool {
 		if pattern == "" {
 			return true // Empty pattern matches any value
 		}
 
-		if caseSensitiveSearch {
-			return value == pattern
-		}
+		return predicateMatches(value, pattern, caseSensitiveSearch)
+	}
 
-		return strings.EqualFold(value, pattern)
+	// Nodes also match on their aliases
+	matchesNode := func(node *Node, pattern string) bool {
+		return pattern == "" || node.matches(pattern, caseSensitiveSearch)
 	}
 
-	// Iterate through all edges
-	for _, toMap := range kg.from {
-		for _, pred := range toMap {
-			if pred == nil || pred.Subject == "" {
-				continue
-			}
+	// Triples inferred by the rules but not materialized are evaluated now
+	candidates := kg.candidatePredicates(pattern.Subject, pattern.Predicate, pattern.Object, caseSensitiveSearch)
+	if pattern.IncludeInferred {
+		candidates = append(candidates, kg.inferredPredicates()...)
+	}
 
-			fromNode := pred.F.(*Node)
-			toNode := pred.T.(*Node)
+	// Check each candidate against the whole pattern
+	for _, pred := range candidates {
+		if pred == nil || pred.Subject == "" || !inGraphs(pred.Graph, pattern.Graphs) {
+			continue
+		}
+		if pred.Inferred() && !pattern.IncludeInferred {
+			continue
+		}
+		if !pattern.AsOf.IsZero() && !pred.Metadata.ValidAt(pattern.AsOf) {
+			continue
+		}
 
-			if fromNode == nil || toNode == nil || fromNode.Lexical == "" || toNode.Lexical == "" {
+		fromNode := pred.F.(*Node)
+		toNode := pred.T.(*Node)
+
+		if fromNode == nil || toNode == nil || fromNode.Lexical == "" || toNode.Lexical == "" {
+			continue
+		}
+
+		// Check if this triple matches the pattern
+		if !matchesNode(fromNode, pattern.Subject) ||
+			!matchesPattern(pred.Subject, pattern.Predicate) ||
+			!matchesNode(toNode, pattern.Object) {
+			continue
+		}
+
+		// Check the condition on the object
+		if pattern.Filter != nil {
+			ok, err := pattern.Filter.Match(toNode, caseSensitiveSearch)
+			if errors.Is(err, ErrTypeMismatch) && pattern.Predicate == "" {
+				// Without a predicate, the other predicates may hold values of other types
+				if mismatch == nil {
+					mismatch = fmt.Errorf("filter %q on (%s, %s, %s): %w", pattern.Filter, fromNode.Lexical, pred.Subject, toNode, err)
+				}
 				continue
 			}
-
-			// Check if this triple matches the pattern
-			if matchesPattern(fromNode.Lexical, subject) &&
-				matchesPattern(pred.Subject, predicate) &&
-				matchesPattern(toNode.Lexical, object) {
-				// Add the matching triple to the result
-				result = append(result, [3]string{fromNode.Lexical, pred.Subject, toNode.Lexical})
+			if err != nil {
+				return nil, fmt.Errorf("filter %q on (%s, %s, %s): %w", pattern.Filter, fromNode.Lexical, pred.Subject, toNode, err)
+			}
+			if toNode.IsLiteral() {
+				comparable = true
+			}
+			if !ok {
+				continue
 			}
 		}
+
+		result = append(result, pred)
 	}
 
-	return result
+	// The filter applies to none of the values
+	if mismatch != nil && !comparable {
+		return nil, mismatch
+	}
+	return result, nil
 }
 
-// DescribeEntity returns all triples where the entity appears as either subject or object.
+// DescribeEntity returns all triples where the entity appears as either subject or object,
+// sorted by subject, predicate and object.
 // This provides a comprehensive view of an entity within the knowledge graph.
+// The triples whose predicate has a declared inverse are also returned as their inverse.
+// The description is restricted to the named graphs if any are given; it covers all graphs otherwise.
 // The caseSensitiveSearch parameter determines if entity matching is case-sensitive.
 // It returns an empty array if the entity does not exist or has no connections.
-func (kg *KG) DescribeEntity(entity string, caseSensitiveSearch bool) [][3]string {
+func (kg *KG) DescribeEntity(entity string, caseSensitiveSearch bool, graphs ...string) [][3]string {
+	return kg.DescribeEntityAsOf(entity, time.Time{}, caseSensitiveSearch, graphs...)
+}
+
+// DescribeEntityAsOf returns the triples where the entity appears, as DescribeEntity does,
+// whose fact holds at the time asOf. A zero asOf returns the triples whatever their validity.
+func (kg *KG) DescribeEntityAsOf(entity string, asOf time.Time, caseSensitiveSearch bool, graphs ...string) [][3]string {
+	triples, _, _ := kg.DescribeEntityPage(entity, asOf, QueryOptions{}, caseSensitiveSearch, graphs...)
+	return triples
+}
+
+// DescribeEntityPage returns a page of the triples where the entity appears, as DescribeEntityAsOf does,
+// in the order given by the options, and the total number of these triples.
+// It returns an error if the options are invalid.
+func (kg *KG) DescribeEntityPage(entity string, asOf time.Time, options QueryOptions, caseSensitiveSearch bool, graphs ...string) ([][3]string, int, error) {
 	// Check for nil graph
 	if kg == nil {
-		return nil
+		return nil, 0, nil
+	}
+	if err := options.Validate(); err != nil {
+		return nil, 0, err
 	}
 
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	var result [][3]string
+	// An empty entity matches any value
+	var candidates []*Predicate
+	if entity == "" {
+		candidates = kg.allPredicates()
+	}
 
-	// If no edges exist, return empty result
-	if kg.from == nil || len(kg.from) == 0 {
-		return result
+	// Collect the outgoing and incoming edges of every matching node, and their inverses
+	for _, node := range kg.lookupNodes(entity, caseSensitiveSearch) {
+		predicates := kg.incidentPredicates(node)
+		candidates = append(candidates, predicates...)
+		candidates = append(candidates, kg.impliedInverses(predicates)...)
 	}
 
-	// Helper function to check if a string matches a pattern (empty pattern matches anything)
-	matchesPattern := func(value, pattern string) bool {
-		if pattern == "" {
-			return true // Empty pattern matches any value
+	// Facts that do not hold at asOf are left out, as well as the triples without a label
+	var predicates []*Predicate
+	for _, pred := range candidates {
+		if inGraphs(pred.Graph, graphs) && (asOf.IsZero() || pred.Metadata.ValidAt(asOf)) && len(appendTriple(nil, pred)) > 0 {
+			predicates = append(predicates, pred)
 		}
+	}
 
-		if caseSensitiveSearch {
-			return value == pattern
-		}
+	var result [][3]string
+	for _, pred := range pageTriples(predicates, options) {
+		result = appendTriple(result, pred)
+	}
+	return result, len(predicates), nil
+}
 
-		return strings.EqualFold(value, pattern)
+// appendTriple appends the [subject, predicate, object] form of pred to triples,
+// skipping predicates or nodes without lexical values.
+func appendTriple(triples [][3]string, pred *Predicate) [][3]string {
+	if pred == nil || pred.Subject == "" {
+		return triples
 	}
 
-	// Get all triples in one pass to avoid nested lock acquisition
-	for _, toMap := range kg.from {
-		for _, pred := range toMap {
-			if pred == nil || pred.Subject == "" {
-				continue
-			}
+	fromNode := pred.F.(*Node)
+	toNode := pred.T.(*Node)
 
-			fromNode := pred.F.(*Node)
-			toNode := pred.T.(*Node)
+	if fromNode == nil || toNode == nil || fromNode.Lexical == "" || toNode.Lexical == "" {
+		return triples
+	}
 
-			if fromNode == nil || toNode == nil || fromNode.Lexical == "" || toNode.Lexical == "" {
-				continue
-			}
+	return append(triples, [3]string{fromNode.Lexical, pred.Subject, toNode.Lexical})
+}
 
-			// Check if entity appears as subject or object
-			if matchesPattern(fromNode.Lexical, entity) || matchesPattern(toNode.Lexical, entity) {
-				// Add the matching triple to the result
-				result = append(result, [3]string{fromNode.Lexical, pred.Subject, toNode.Lexical})
-			}
-		}
+// adjacentPredicates returns the predicates of an adjacency map, such as kg.from[id] or kg.to[id].
+func adjacentPredicates(adjacency map[int64][]*Predicate) []*Predicate {
+	var result []*Predicate
+	for _, lines := range adjacency {
+		result = append(result, lines...)
 	}
+	return result
+}
 
+// allPredicates returns every predicate of the graph.
+// The caller must hold at least a read lock.
+func (kg *KG) allPredicates() []*Predicate {
+	var result []*Predicate
+	for _, toMap := range kg.from {
+		for _, lines := range toMap {
+			result = append(result, lines...)
+		}
+	}
 	return result
 }
+
+// candidatePredicates returns a superset of the predicates matching the given pattern,
+// using the most selective index available for the bound parts of the pattern.
+// Empty parameters are wildcards. The caller must hold at least a read lock.
+func (kg *KG) candidatePredicates(subject, predicate, object string, caseSensitiveSearch bool) []*Predicate {
+	switch {
+	case subject != "":
+		var result []*Predicate
+		for _, node := range kg.lookupNodes(subject, caseSensitiveSearch) {
+			for _, lines := range kg.from[node.ID()] {
+				result = append(result, lines...)
+			}
+		}
+		return result
+	case object != "":
+		var result []*Predicate
+		objectNodes := kg.lookupNodes(object, caseSensitiveSearch)
+		objectNodes = append(objectNodes, kg.lookupLiterals(object, caseSensitiveSearch)...)
+		for _, node := range objectNodes {
+			for _, lines := range kg.to[node.ID()] {
+				result = append(result, lines...)
+			}
+		}
+		return result
+	case predicate != "":
+		return kg.lookupPredicates(predicate, caseSensitiveSearch)
+	default:
+		return kg.allPredicates()
+	}
+}
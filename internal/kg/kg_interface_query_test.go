@@ -56,7 +56,8 @@ func TestListPredicatesFromNode(t *testing.T) {
 	assert := assert.New(t)
 
 	// Test for existing node with predicates
-	predicates := kg.ListPredicatesFromNode("Alice", true)
+	predicates, err := kg.ListPredicatesFromNode("Alice", true)
+	assert.NoError(err)
 	assert.NotNil(predicates, "Predicates should not be nil for existing node")
 	assert.Equal(3, len(predicates), "Alice should have 3 outgoing predicates")
 
@@ -69,16 +70,19 @@ func TestListPredicatesFromNode(t *testing.T) {
 	assert.Equal([]string{"friendOf", "knows", "owns"}, subjects, "Predicate subjects should match")
 
 	// Test for existing node with no predicates
-	noPredicates := kg.ListPredicatesFromNode("Car", true)
+	noPredicates, err := kg.ListPredicatesFromNode("Car", true)
+	assert.NoError(err)
 	assert.NotNil(noPredicates, "Result should not be nil for node with no predicates")
 	assert.Empty(noPredicates, "Car should have no outgoing predicates")
 
 	// Test for non-existing node
-	nilPredicates := kg.ListPredicatesFromNode("NonExistent", true)
+	nilPredicates, err := kg.ListPredicatesFromNode("NonExistent", true)
+	assert.NoError(err)
 	assert.Nil(nilPredicates, "Result should be nil for non-existent node")
 
 	// Test case-insensitive search
-	caseInsensitivePredicates := kg.ListPredicatesFromNode("alice", false)
+	caseInsensitivePredicates, err := kg.ListPredicatesFromNode("alice", false)
+	assert.NoError(err)
 	assert.NotNil(caseInsensitivePredicates, "Predicates should be found with case-insensitive search")
 	assert.Equal(3, len(caseInsensitivePredicates), "alice (case-insensitive) should have 3 outgoing predicates")
 }
@@ -88,7 +92,8 @@ func TestListPredicatesToNode(t *testing.T) {
 	assert := assert.New(t)
 
 	// Test for existing node with incoming predicates
-	predicates := kg.ListPredicatesToNode("Book", true)
+	predicates, err := kg.ListPredicatesToNode("Book", true)
+	assert.NoError(err)
 	assert.NotNil(predicates, "Predicates should not be nil for existing node")
 	assert.Equal(2, len(predicates), "Book should have 2 incoming predicates")
 
@@ -101,16 +106,19 @@ func TestListPredicatesToNode(t *testing.T) {
 	assert.Equal([]string{"likes", "owns"}, subjects, "Predicate subjects should match")
 
 	// Test for existing node with no incoming predicates
-	noPredicates := kg.ListPredicatesToNode("Alice", true)
+	noPredicates, err := kg.ListPredicatesToNode("Alice", true)
+	assert.NoError(err)
 	assert.NotNil(noPredicates, "Result should not be nil for node with no incoming predicates")
 	assert.Empty(noPredicates, "Alice should have no incoming predicates")
 
 	// Test for non-existing node
-	nilPredicates := kg.ListPredicatesToNode("NonExistent", true)
+	nilPredicates, err := kg.ListPredicatesToNode("NonExistent", true)
+	assert.NoError(err)
 	assert.Nil(nilPredicates, "Result should be nil for non-existent node")
 
 	// Test case-insensitive search
-	caseInsensitivePredicates := kg.ListPredicatesToNode("book", false)
+	caseInsensitivePredicates, err := kg.ListPredicatesToNode("book", false)
+	assert.NoError(err)
 	assert.NotNil(caseInsensitivePredicates, "Predicates should be found with case-insensitive search")
 	assert.Equal(2, len(caseInsensitivePredicates), "book (case-insensitive) should have 2 incoming predicates")
 }
@@ -120,23 +128,41 @@ func TestPredicatesFromTo(t *testing.T) {
 	assert := assert.New(t)
 
 	// Test for existing connection
-	predicates := kg.PredicatesFromTo("Alice", "Bob", true)
+	predicates, err := kg.PredicatesFromTo("Alice", "Bob", true)
+	assert.NoError(err)
 	assert.NotNil(predicates, "Predicates should not be nil for existing connection")
 	assert.Equal(1, len(predicates), "Should find 1 predicate from Alice to Bob")
 	assert.Equal("knows", predicates[0].Subject, "Predicate subject should be 'knows'")
 
 	// Test for non-existing connection
-	nilPredicates := kg.PredicatesFromTo("Bob", "Alice", true)
+	nilPredicates, err := kg.PredicatesFromTo("Bob", "Alice", true)
+	assert.NoError(err)
 	assert.Nil(nilPredicates, "Result should be nil for non-existent connection")
 
 	// Test for non-existing nodes
-	assert.Nil(kg.PredicatesFromTo("NonExistent", "Alice", true), "Result should be nil if from node doesn't exist")
-	assert.Nil(kg.PredicatesFromTo("Alice", "NonExistent", true), "Result should be nil if to node doesn't exist")
+	nilPredicates, err = kg.PredicatesFromTo("NonExistent", "Alice", true)
+	assert.NoError(err)
+	assert.Nil(nilPredicates, "Result should be nil if from node doesn't exist")
+	nilPredicates, err = kg.PredicatesFromTo("Alice", "NonExistent", true)
+	assert.NoError(err)
+	assert.Nil(nilPredicates, "Result should be nil if to node doesn't exist")
 
 	// Test case-insensitive search
-	caseInsensitivePredicates := kg.PredicatesFromTo("alice", "bob", false)
+	caseInsensitivePredicates, err := kg.PredicatesFromTo("alice", "bob", false)
+	assert.NoError(err)
 	assert.NotNil(caseInsensitivePredicates, "Predicates should be found with case-insensitive search")
 	assert.Equal(1, len(caseInsensitivePredicates), "Should find 1 predicate from Alice to Bob with case-insensitive search")
+
+	// Test several predicates between the same nodes
+	assert.NoError(kg.InsertTriple("Alice", "trusts", "Bob", true))
+	predicates, err = kg.PredicatesFromTo("Alice", "Bob", true)
+	assert.NoError(err)
+	subjects := make([]string, 0, len(predicates))
+	for _, pred := range predicates {
+		subjects = append(subjects, pred.Subject)
+	}
+	sort.Strings(subjects)
+	assert.Equal([]string{"knows", "trusts"}, subjects, "Both predicates from Alice to Bob should be returned")
 }
 
 func TestQueryBySubject(t *testing.T) {
@@ -144,7 +170,8 @@ func TestQueryBySubject(t *testing.T) {
 	assert := assert.New(t)
 
 	// Test for existing subject with predicates
-	results := kg.QueryBySubject("Alice", true)
+	results, err := kg.QueryBySubject("Alice", true)
+	assert.NoError(err)
 	assert.NotNil(results, "Results should not be nil for existing subject")
 	assert.Equal(3, len(results), "Alice should have 3 different predicate types")
 
@@ -159,16 +186,19 @@ func TestQueryBySubject(t *testing.T) {
 	assert.Contains(results["owns"], "Book", "Alice owns a Book")
 
 	// Test for existing subject with no predicates
-	emptyResults := kg.QueryBySubject("Car", true)
+	emptyResults, err := kg.QueryBySubject("Car", true)
+	assert.NoError(err)
 	assert.NotNil(emptyResults, "Results should not be nil for subject with no predicates")
 	assert.Empty(emptyResults, "Car should have no predicates")
 
 	// Test for non-existing subject
-	nilResults := kg.QueryBySubject("NonExistent", true)
+	nilResults, err := kg.QueryBySubject("NonExistent", true)
+	assert.NoError(err)
 	assert.Nil(nilResults, "Results should be nil for non-existent subject")
 
 	// Test case-insensitive search
-	caseInsensitiveResults := kg.QueryBySubject("alice", false)
+	caseInsensitiveResults, err := kg.QueryBySubject("alice", false)
+	assert.NoError(err)
 	assert.NotNil(caseInsensitiveResults, "Results should be found with case-insensitive search")
 	assert.Equal(3, len(caseInsensitiveResults), "alice (case-insensitive) should have 3 different predicate types")
 }
@@ -178,7 +208,8 @@ func TestQueryByObject(t *testing.T) {
 	assert := assert.New(t)
 
 	// Test for existing object with incoming predicates
-	results := kg.QueryByObject("Book", true)
+	results, err := kg.QueryByObject("Book", true)
+	assert.NoError(err)
 	assert.NotNil(results, "Results should not be nil for existing object")
 	assert.Equal(2, len(results), "Book should have 2 different predicate types pointing to it")
 
@@ -190,16 +221,19 @@ func TestQueryByObject(t *testing.T) {
 	assert.Contains(results["likes"], "Charlie", "Charlie likes the Book")
 
 	// Test for existing object with no incoming predicates
-	emptyResults := kg.QueryByObject("Alice", true)
+	emptyResults, err := kg.QueryByObject("Alice", true)
+	assert.NoError(err)
 	assert.NotNil(emptyResults, "Results should not be nil for object with no incoming predicates")
 	assert.Empty(emptyResults, "Alice should have no incoming predicates")
 
 	// Test for non-existing object
-	nilResults := kg.QueryByObject("NonExistent", true)
+	nilResults, err := kg.QueryByObject("NonExistent", true)
+	assert.NoError(err)
 	assert.Nil(nilResults, "Results should be nil for non-existent object")
 
 	// Test case-insensitive search
-	caseInsensitiveResults := kg.QueryByObject("book", false)
+	caseInsensitiveResults, err := kg.QueryByObject("book", false)
+	assert.NoError(err)
 	assert.NotNil(caseInsensitiveResults, "Results should be found with case-insensitive search")
 	assert.Equal(2, len(caseInsensitiveResults), "book (case-insensitive) should have 2 different predicate types pointing to it")
 }
@@ -287,19 +321,31 @@ func TestQueryEdgeCases(t *testing.T) {
 	assert := assert.New(t)
 
 	// Test with empty subject/object/predicate
-	emptySubject := kg.QueryBySubject("", true)
+	emptySubject, err := kg.QueryBySubject("", true)
+	assert.NoError(err)
 	assert.Nil(emptySubject, "Empty subject should return nil")
 
-	emptyObject := kg.QueryByObject("", true)
+	emptyObject, err := kg.QueryByObject("", true)
+	assert.NoError(err)
 	assert.Nil(emptyObject, "Empty object should return nil")
 
 	// Test with nil graph
 	var nilKG *KG
-	assert.Nil(nilKG.ListPredicatesFromNode("Alice", true), "Nil graph should handle method calls safely")
-	assert.Nil(nilKG.ListPredicatesToNode("Alice", true), "Nil graph should handle method calls safely")
-	assert.Nil(nilKG.PredicatesFromTo("Alice", "Bob", true), "Nil graph should handle method calls safely")
-	assert.Nil(nilKG.QueryBySubject("Alice", true), "Nil graph should handle method calls safely")
-	assert.Nil(nilKG.QueryByObject("Book", true), "Nil graph should handle method calls safely")
+	nilPredicates, err := nilKG.ListPredicatesFromNode("Alice", true)
+	assert.NoError(err)
+	assert.Nil(nilPredicates, "Nil graph should handle method calls safely")
+	nilPredicates, err = nilKG.ListPredicatesToNode("Alice", true)
+	assert.NoError(err)
+	assert.Nil(nilPredicates, "Nil graph should handle method calls safely")
+	nilPredicates, err = nilKG.PredicatesFromTo("Alice", "Bob", true)
+	assert.NoError(err)
+	assert.Nil(nilPredicates, "Nil graph should handle method calls safely")
+	nilResults, err := nilKG.QueryBySubject("Alice", true)
+	assert.NoError(err)
+	assert.Nil(nilResults, "Nil graph should handle method calls safely")
+	nilResults, err = nilKG.QueryByObject("Book", true)
+	assert.NoError(err)
+	assert.Nil(nilResults, "Nil graph should handle method calls safely")
 	assert.Nil(nilKG.QueryByPredicate("owns", true), "Nil graph should handle method calls safely")
 	assert.Nil(nilKG.FindTriples("Alice", "owns", "Book", true), "Nil graph should handle method calls safely")
 }
@@ -0,0 +1,180 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AddType asserts that the entity is an instance of the class, by inserting the triple (entity, rdf:type, class).
+// Both the entity and the class are created if they do not exist.
+// The triple is inserted into each of the named graphs, or into the default graph if none are given.
+// The caseSensitiveSearch parameter determines if the entity and class lookups are case-sensitive.
+func (kg *KG) AddType(entity, class string, caseSensitiveSearch bool, graphs ...string) error {
+	return kg.InsertTriple(entity, TypePredicate, class, caseSensitiveSearch, graphs...)
+}
+
+// AddSubClass asserts that every instance of class is also an instance of superClass,
+// by inserting the triple (class, rdfs:subClassOf, superClass).
+// Both classes are created if they do not exist.
+// The triple is inserted into each of the named graphs, or into the default graph if none are given.
+// The caseSensitiveSearch parameter determines if the class lookups are case-sensitive.
+// It returns an error if the class is a superclass of itself.
+func (kg *KG) AddSubClass(class, superClass string, caseSensitiveSearch bool, graphs ...string) error {
+	if predicateMatches(class, superClass, caseSensitiveSearch) {
+		return fmt.Errorf("class %q cannot be a subclass of itself", class)
+	}
+	return kg.InsertTriple(class, SubClassOfPredicate, superClass, caseSensitiveSearch, graphs...)
+}
+
+// Types returns the labels of the classes the entity is an instance of, sorted.
+// If inherited is true, the superclasses of these classes are included, transitively.
+// The types are restricted to the named graphs if any are given; they cover all graphs otherwise.
+// The caseSensitiveSearch parameter determines if the entity lookup is case-sensitive.
+// It returns an error if the entity does not exist or is ambiguous.
+func (kg *KG) Types(entity string, inherited, caseSensitiveSearch bool, graphs ...string) ([]string, error) {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	node, err := kg.resolveEntity(entity, caseSensitiveSearch)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("entity %q not found", entity)
+	}
+
+	classes := kg.directTypes(node, graphs)
+	if inherited {
+		classes = append(classes, kg.reachable(classes, SubClassOfPredicate, true, graphs)...)
+	}
+	return sortedLabels(classes), nil
+}
+
+// SuperClasses returns the labels of the superclasses of the class, transitively, sorted.
+// The class itself is not included, unless the hierarchy has a cycle.
+// The hierarchy is restricted to the named graphs if any are given; it covers all graphs otherwise.
+// The caseSensitiveSearch parameter determines if the class lookup is case-sensitive.
+// It returns nil if the class does not exist.
+func (kg *KG) SuperClasses(class string, caseSensitiveSearch bool, graphs ...string) []string {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return sortedLabels(kg.reachable(kg.lookupNodes(class, caseSensitiveSearch), SubClassOfPredicate, true, graphs))
+}
+
+// SubClasses returns the labels of the subclasses of the class, transitively, sorted.
+// The class itself is not included, unless the hierarchy has a cycle.
+// The hierarchy is restricted to the named graphs if any are given; it covers all graphs otherwise.
+// The caseSensitiveSearch parameter determines if the class lookup is case-sensitive.
+// It returns nil if the class does not exist.
+func (kg *KG) SubClasses(class string, caseSensitiveSearch bool, graphs ...string) []string {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return sortedLabels(kg.reachable(kg.lookupNodes(class, caseSensitiveSearch), SubClassOfPredicate, false, graphs))
+}
+
+// ListInstances returns the entities that are instances of the class or of any of its subclasses, transitively,
+// sorted by label. Every entity matching the class label is considered as the class.
+// The type assertions and the hierarchy are restricted to the named graphs if any are given;
+// they cover all graphs otherwise.
+// The caseSensitiveSearch parameter determines if the class lookup is case-sensitive.
+// It returns nil if the class does not exist or has no instances.
+func (kg *KG) ListInstances(class string, caseSensitiveSearch bool, graphs ...string) []*Node {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	classes := kg.lookupNodes(class, caseSensitiveSearch)
+	classes = append(classes, kg.reachable(classes, SubClassOfPredicate, false, graphs)...)
+
+	seen := make(map[int64]bool)
+	var instances []*Node
+	for _, classNode := range classes {
+		for _, lines := range kg.to[classNode.ID()] {
+			for _, pred := range lines {
+				if predicateMatches(pred.Subject, TypePredicate, false) && inGraphs(pred.Graph, graphs) && !seen[pred.F.ID()] {
+					seen[pred.F.ID()] = true
+					instances = append(instances, pred.F.(*Node))
+				}
+			}
+		}
+	}
+
+	sortNodes(instances)
+	return instances
+}
+
+// reachable returns the nodes reached from the start nodes by following one or more predicates named predicate,
+// from subject to object if forward is true and from object to subject otherwise.
+// A start node is only included if it can be reached from a start node, through a cycle.
+// The caller must hold at least a read lock.
+func (kg *KG) reachable(start []*Node, predicate string, forward bool, graphs []string) []*Node {
+	seen := make(map[int64]bool)
+	var result []*Node
+	queue := append([]*Node(nil), start...)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		adjacency := kg.to[node.ID()]
+		if forward {
+			adjacency = kg.from[node.ID()]
+		}
+		for _, lines := range adjacency {
+			for _, pred := range lines {
+				if !predicateMatches(pred.Subject, predicate, false) || !inGraphs(pred.Graph, graphs) {
+					continue
+				}
+				next := pred.F.(*Node)
+				if forward {
+					next = pred.T.(*Node)
+				}
+				if !seen[next.ID()] && !next.IsLiteral() {
+					seen[next.ID()] = true
+					result = append(result, next)
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// directTypes returns the classes the node is asserted to be an instance of.
+// The caller must hold at least a read lock.
+func (kg *KG) directTypes(node *Node, graphs []string) []*Node {
+	var classes []*Node
+	for _, lines := range kg.from[node.ID()] {
+		for _, pred := range lines {
+			if classNode := pred.T.(*Node); predicateMatches(pred.Subject, TypePredicate, false) &&
+				inGraphs(pred.Graph, graphs) && !classNode.IsLiteral() {
+				classes = append(classes, classNode)
+			}
+		}
+	}
+	return classes
+}
+
+// sortedLabels returns the distinct lexical values of the nodes, sorted.
+func sortedLabels(nodes []*Node) []string {
+	seen := make(map[string]bool, len(nodes))
+	var labels []string
+	for _, node := range nodes {
+		if !seen[node.Lexical] {
+			seen[node.Lexical] = true
+			labels = append(labels, node.Lexical)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// sortNodes sorts the nodes by lexical value, then by ID.
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Lexical != nodes[j].Lexical {
+			return nodes[i].Lexical < nodes[j].Lexical
+		}
+		return nodes[i].ID() < nodes[j].ID()
+	})
+}
@@ -0,0 +1,70 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassHierarchy(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.AddSubClass("Interpreted Language", "Programming Language", true))
+	assert.NoError(kg.AddSubClass("Compiled Language", "Programming Language", true))
+	assert.NoError(kg.AddSubClass("Scripting Language", "Interpreted Language", true))
+	assert.Error(kg.AddSubClass("Programming Language", "Programming Language", true))
+
+	assert.NoError(kg.AddType("Python", "Interpreted Language", true))
+	assert.NoError(kg.AddType("Python", "Scripting Language", true))
+	assert.NoError(kg.AddType("Go", "Compiled Language", true))
+	assert.NoError(kg.AddType("Bash", "Scripting Language", true, "shells"))
+	assert.NoError(kg.AddType("Lisp", "Programming Language", true))
+	assert.NoError(kg.AddType("Guido", "Person", true))
+
+	labels := func(nodes []*Node) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Lexical)
+		}
+		return result
+	}
+
+	assert.Equal([]string{"Bash", "Go", "Lisp", "Python"}, labels(kg.ListInstances("Programming Language", true)), "Instances of subclasses are included, once")
+	assert.Equal([]string{"Bash", "Python"}, labels(kg.ListInstances("Interpreted Language", true)))
+	assert.Equal([]string{"Bash", "Python"}, labels(kg.ListInstances("scripting language", false)))
+	assert.Equal([]string{"Go", "Lisp", "Python"}, labels(kg.ListInstances("Programming Language", true, DefaultGraph)))
+	assert.Empty(kg.ListInstances("Unknown", true))
+
+	assert.Equal([]string{"Interpreted Language", "Programming Language"}, kg.SuperClasses("Scripting Language", true))
+	assert.Equal([]string{"Compiled Language", "Interpreted Language", "Scripting Language"}, kg.SubClasses("Programming Language", true))
+	assert.Nil(kg.SuperClasses("Programming Language", true))
+
+	types, err := kg.Types("Python", false, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Interpreted Language", "Scripting Language"}, types)
+	types, err = kg.Types("Python", true, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Interpreted Language", "Programming Language", "Scripting Language"}, types)
+	_, err = kg.Types("Ruby", true, true)
+	assert.Error(err)
+
+	// A cycle in the hierarchy does not loop forever
+	assert.NoError(kg.AddSubClass("Programming Language", "Scripting Language", true))
+	assert.Equal([]string{"Bash", "Go", "Lisp", "Python"}, labels(kg.ListInstances("Scripting Language", true)))
+	assert.Contains(kg.SuperClasses("Programming Language", true), "Programming Language")
+}
+
+func TestSchemaWithClassHierarchy(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.SetSchema(newTestSchema(EnforcementStrict)))
+	assert.NoError(kg.AddSubClass("Manager", "Person", true))
+	assert.Error(kg.AddSubClass("Manager", "Employee", true), "Employee is not a declared class")
+	assert.NoError(kg.AddType("Bob", "Manager", true))
+	assert.NoError(kg.AddType("Carol", "Manager", true))
+
+	// A Manager is a Person, the domain of worksFor
+	assert.NoError(kg.InsertTriple("Bob", "worksFor", "Carol", true))
+}
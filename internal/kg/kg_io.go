@@ -9,17 +9,143 @@ import (
 // SerializablePredicate represents a serializable version of a Predicate.
 // It stores node references as IDs rather than pointers to enable serialization.
 type SerializablePredicate struct {
-	FromID  int64  // ID of the source node
-	ToID    int64  // ID of the target node
-	Subject string // Subject of the predicate
+	ID       int64    // Unique identifier of the predicate
+	FromID   int64    // ID of the source node
+	ToID     int64    // ID of the target node
+	Subject  string   // Subject of the predicate
+	Graph    string   // Name of the graph holding the triple
+	Metadata Metadata // Provenance of the triple
+	Rule     string   // Label of the rule that inferred the triple, empty for asserted triples
 }
 
 // SerializableKG is a serializable representation of the knowledge graph.
 // It converts the graph structure to a format that can be easily serialized.
 type SerializableKG struct {
-	Nodes     map[int64]*Node         // All nodes in the graph
-	Edges     []SerializablePredicate // All edges in a serializable format
-	CurrentID int64                   // The current ID counter for node creation
+	Nodes         map[int64]*Node         // All nodes in the graph
+	Edges         []SerializablePredicate // All edges in a serializable format
+	CurrentID     int64                   // The current ID counter for node creation
+	CurrentLineID int64                   // The current ID counter for predicate creation
+	Schema        *Schema                 // The active ontology of the graph, if any
+	Rules         []Rule                  // The rules inferring triples from the others
+	Declarations  []PredicateDeclaration  // The declared properties of predicates
+	History       []Revision              // The recorded changes of the graph, oldest first
+}
+
+// newSerializablePredicate converts a predicate to its serializable form.
+func newSerializablePredicate(pred *Predicate) SerializablePredicate {
+	return SerializablePredicate{
+		ID:       pred.Identifier,
+		FromID:   pred.F.ID(),
+		ToID:     pred.T.ID(),
+		Subject:  pred.Subject,
+		Graph:    pred.Graph,
+		Metadata: pred.Metadata,
+		Rule:     pred.Rule,
+	}
+}
+
+// newPredicate converts a serializable predicate back to a predicate between the nodes of the graph.
+// It returns nil if one of the nodes does not exist.
+// The caller must hold at least a read lock on the graph.
+func (kg *KG) newPredicate(edge SerializablePredicate) *Predicate {
+	fromNode := kg.nodes[edge.FromID]
+	toNode := kg.nodes[edge.ToID]
+	if fromNode == nil || toNode == nil {
+		return nil
+	}
+	return &Predicate{
+		F:          fromNode,
+		T:          toNode,
+		Subject:    edge.Subject,
+		Identifier: edge.ID,
+		Graph:      edge.Graph,
+		Metadata:   edge.Metadata,
+		Rule:       edge.Rule,
+	}
+}
+
+// newSerializableKG converts the knowledge graph to its serializable representation.
+// The caller must hold at least a read lock on the graph.
+func newSerializableKG(kg *KG) SerializableKG {
+	serialKG := SerializableKG{
+		Nodes:         kg.nodes,
+		Edges:         make([]SerializablePredicate, 0),
+		CurrentID:     kg.currentID,
+		CurrentLineID: kg.currentLineID,
+		Schema:        kg.schema,
+		Rules:         kg.rules,
+		Declarations:  kg.sortedDeclarations(),
+		History:       kg.history,
+	}
+
+	// Convert predicates to serializable form
+	for _, pred := range kg.allPredicates() {
+		serialKG.Edges = append(serialKG.Edges, newSerializablePredicate(pred))
+	}
+
+	return serialKG
+}
+
+// newKGFromSerializable converts a serializable representation back
+// to a proper KG structure with all node and predicate relationships.
+func newKGFromSerializable(serialKG SerializableKG) *KG {
+	nodes := serialKG.Nodes
+	if nodes == nil {
+		nodes = make(map[int64]*Node)
+	}
+
+	// Create a new KG with the decoded data
+	kg := &KG{
+		nodes:         nodes,
+		from:          make(map[int64]map[int64][]*Predicate),
+		to:            make(map[int64]map[int64][]*Predicate),
+		currentID:     serialKG.CurrentID,
+		currentLineID: serialKG.CurrentLineID,
+		schema:        serialKG.Schema,
+		rules:         serialKG.Rules,
+		history:       serialKG.History,
+	}
+
+	for _, declaration := range serialKG.Declarations {
+		*kg.declaration(declaration.Name) = declaration
+	}
+
+	// Make sure the line counter is beyond any stored predicate ID
+	for _, edge := range serialKG.Edges {
+		if edge.ID >= kg.currentLineID {
+			kg.currentLineID = edge.ID + 1
+		}
+	}
+
+	// Reconstruct predicates
+	seen := make(map[int64]bool, len(serialKG.Edges))
+	for _, edge := range serialKG.Edges {
+		if kg.nodes[edge.FromID] == nil || kg.nodes[edge.ToID] == nil {
+			continue // Skip if nodes don't exist
+		}
+
+		// Graphs written before predicates had an identifier all carry the zero ID
+		id := edge.ID
+		if seen[id] {
+			id = kg.currentLineID
+			kg.currentLineID++
+		}
+		seen[id] = true
+
+		// Create the predicate and set it in both maps
+		edge.ID = id
+		kg.addPredicate(kg.newPredicate(edge))
+	}
+
+	// Graphs written before entities had keys get generated ones
+	for id, node := range kg.nodes {
+		if node != nil && node.Key == "" && node.Lexical != "" && !node.IsLiteral() {
+			node.Key = generatedKey(id)
+		}
+	}
+
+	kg.rebuildIndex()
+	return kg
 }
 
 // WriteTo serializes and writes the knowledge graph to the provided writer
@@ -27,34 +153,16 @@ type SerializableKG struct {
 // that the graph structure can be properly encoded.
 func WriteTo(w io.Writer, kg *KG) error {
 	// Acquire a read lock to ensure the graph isn't modified during serialization
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
-	
+
 	encoder := gob.NewEncoder(w)
 
 	// Register the Node type with gob
 	gob.Register(&Node{})
 
-	// Create a serializable representation of the KG
-	serialKG := SerializableKG{
-		Nodes:     kg.nodes,
-		Edges:     make([]SerializablePredicate, 0),
-		CurrentID: kg.currentID,
-	}
-
-	// Convert predicates to serializable form
-	for fromID, toMap := range kg.from {
-		for toID, pred := range toMap {
-			serialKG.Edges = append(serialKG.Edges, SerializablePredicate{
-				FromID:  fromID,
-				ToID:    toID,
-				Subject: pred.Subject,
-			})
-		}
-	}
-
 	// Encode the serializable representation
-	return encoder.Encode(serialKG)
+	return encoder.Encode(newSerializableKG(kg))
 }
 
 // ReadFrom deserializes a knowledge graph from the provided reader
@@ -75,47 +183,7 @@ func ReadFrom(r io.Reader) (*KG, error) {
 		return nil, err
 	}
 
-	// Create a new KG with the decoded data
-	kg := &KG{
-		nodes:     serialKG.Nodes,
-		from:      make(map[int64]map[int64]*Predicate),
-		to:        make(map[int64]map[int64]*Predicate),
-		currentID: serialKG.CurrentID,
-	}
-
-	// Reconstruct predicates
-	for _, edge := range serialKG.Edges {
-		fromID := edge.FromID
-		toID := edge.ToID
-
-		fromNode := kg.nodes[fromID]
-		toNode := kg.nodes[toID]
-
-		if fromNode == nil || toNode == nil {
-			continue // Skip if nodes don't exist
-		}
-
-		// Create the predicate
-		pred := &Predicate{
-			F:       fromNode,
-			T:       toNode,
-			Subject: edge.Subject,
-		}
-
-		// Initialize maps if needed
-		if kg.from[fromID] == nil {
-			kg.from[fromID] = make(map[int64]*Predicate)
-		}
-		if kg.to[toID] == nil {
-			kg.to[toID] = make(map[int64]*Predicate)
-		}
-
-		// Set the predicate in both maps
-		kg.from[fromID][toID] = pred
-		kg.to[toID][fromID] = pred
-	}
-
-	return kg, nil
+	return newKGFromSerializable(serialKG), nil
 }
 
 // SaveToJSON serializes and writes the knowledge graph to the provided writer
@@ -123,31 +191,13 @@ func ReadFrom(r io.Reader) (*KG, error) {
 // that the graph structure can be properly encoded in JSON format.
 func SaveToJSON(w io.Writer, kg *KG) error {
 	// Acquire a read lock to ensure the graph isn't modified during serialization
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
-	
-	encoder := json.NewEncoder(w)
 
-	// Create a serializable representation of the KG
-	serialKG := SerializableKG{
-		Nodes:     kg.nodes,
-		Edges:     make([]SerializablePredicate, 0),
-		CurrentID: kg.currentID,
-	}
-
-	// Convert predicates to serializable form
-	for fromID, toMap := range kg.from {
-		for toID, pred := range toMap {
-			serialKG.Edges = append(serialKG.Edges, SerializablePredicate{
-				FromID:  fromID,
-				ToID:    toID,
-				Subject: pred.Subject,
-			})
-		}
-	}
+	encoder := json.NewEncoder(w)
 
 	// Encode the serializable representation
-	return encoder.Encode(serialKG)
+	return encoder.Encode(newSerializableKG(kg))
 }
 
 // ReadFromJSON deserializes a knowledge graph from the provided reader
@@ -165,45 +215,5 @@ func ReadFromJSON(r io.Reader) (*KG, error) {
 		return nil, err
 	}
 
-	// Create a new KG with the decoded data
-	kg := &KG{
-		nodes:     serialKG.Nodes,
-		from:      make(map[int64]map[int64]*Predicate),
-		to:        make(map[int64]map[int64]*Predicate),
-		currentID: serialKG.CurrentID,
-	}
-
-	// Reconstruct predicates
-	for _, edge := range serialKG.Edges {
-		fromID := edge.FromID
-		toID := edge.ToID
-
-		fromNode := kg.nodes[fromID]
-		toNode := kg.nodes[toID]
-
-		if fromNode == nil || toNode == nil {
-			continue // Skip if nodes don't exist
-		}
-
-		// Create the predicate
-		pred := &Predicate{
-			F:       fromNode,
-			T:       toNode,
-			Subject: edge.Subject,
-		}
-
-		// Initialize maps if needed
-		if kg.from[fromID] == nil {
-			kg.from[fromID] = make(map[int64]*Predicate)
-		}
-		if kg.to[toID] == nil {
-			kg.to[toID] = make(map[int64]*Predicate)
-		}
-
-		// Set the predicate in both maps
-		kg.from[fromID][toID] = pred
-		kg.to[toID][fromID] = pred
-	}
-
-	return kg, nil
+	return newKGFromSerializable(serialKG), nil
 }
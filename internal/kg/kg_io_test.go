@@ -575,3 +575,94 @@ func TestNodeIDPreservation(t *testing.T) {
 	assert.True(deserializedJSON.HasEdgeFromTo(100, 200), "Edge from 100 to 200 should be preserved in JSON")
 	assert.True(deserializedJSON.HasEdgeFromTo(200, 300), "Edge from 200 to 300 should be preserved in JSON")
 }
+
+func TestMultigraph(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	var _ graph.Directed = kg
+	var _ graph.DirectedMultigraph = kg
+	var _ graph.UndirectedMultigraph = kg
+
+	assert.NoError(kg.InsertTriple("Python", "created_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Python", "maintained_by", "Guido", true))
+	assert.NoError(kg.InsertTriple("Guido", "works_on", "Python", true))
+
+	python := kg.FindNode("Python", true)
+	guido := kg.FindNode("Guido", true)
+
+	// Test Lines method
+	lines := kg.Lines(python.ID(), guido.ID())
+	assert.Equal(2, lines.Len(), "There should be 2 lines from Python to Guido")
+	ids := make(map[int64]bool)
+	for lines.Next() {
+		ids[lines.Line().ID()] = true
+	}
+	assert.Len(ids, 2, "Lines should have distinct IDs")
+	assert.Equal(0, kg.Lines(python.ID(), 999).Len(), "No lines should exist to a non-existent node")
+
+	// Test LinesBetween method
+	assert.Equal(3, kg.LinesBetween(guido.ID(), python.ID()).Len(), "LinesBetween should ignore direction")
+
+	// Test NewLine and SetLine methods
+	line := kg.NewLine(python, guido).(*Predicate)
+	line.Subject = "admired_by"
+	kg.SetLine(line)
+	assert.Equal(3, kg.Lines(python.ID(), guido.ID()).Len(), "SetLine should add a new line")
+	kg.SetLine(line)
+	assert.Equal(3, kg.Lines(python.ID(), guido.ID()).Len(), "SetLine with an existing line ID should replace it")
+
+	// Test ReversedLine method
+	reversed := line.ReversedLine()
+	assert.Equal(line.ID(), reversed.ID(), "Reversed line should keep its ID")
+	assert.Equal(guido.ID(), reversed.From().ID(), "Reversed line's From should be original line's To")
+
+	// Test serialization round trips
+	var gobBuf bytes.Buffer
+	assert.NoError(WriteTo(&gobBuf, kg))
+	fromGob, err := ReadFrom(&gobBuf)
+	assert.NoError(err)
+	assert.Len(fromGob.FindTriples("Python", "", "Guido", true), 3, "All predicates should survive a gob round trip")
+
+	var jsonBuf bytes.Buffer
+	assert.NoError(SaveToJSON(&jsonBuf, kg))
+	fromJSON, err := ReadFromJSON(&jsonBuf)
+	assert.NoError(err)
+	assert.Len(fromJSON.FindTriples("Python", "", "Guido", true), 3, "All predicates should survive a JSON round trip")
+
+	// New predicates must not reuse identifiers of deserialized ones
+	assert.NoError(fromGob.InsertTriple("Guido", "created", "Python", true))
+	ids = make(map[int64]bool)
+	for _, subject := range []string{"Python", "Guido"} {
+		predicates, err := fromGob.ListPredicatesFromNode(subject, true)
+		assert.NoError(err)
+		for _, pred := range predicates {
+			ids[pred.ID()] = true
+		}
+	}
+	assert.Len(ids, 5, "All predicates should have distinct IDs")
+}
+
+func TestNamedGraphSerialization(t *testing.T) {
+	assert := assert.New(t)
+
+	original := NewKG("sample")
+	assert.NoError(original.InsertTriple("Python", "used_by", "Alice", true))
+	assert.NoError(original.InsertTriple("Python", "used_by", "Alice", true, "project-a"))
+
+	var gobBuf bytes.Buffer
+	assert.NoError(WriteTo(&gobBuf, original))
+	fromGob, err := ReadFrom(&gobBuf)
+	assert.NoError(err)
+
+	var jsonBuf bytes.Buffer
+	assert.NoError(SaveToJSON(&jsonBuf, original))
+	fromJSON, err := ReadFromJSON(&jsonBuf)
+	assert.NoError(err)
+
+	for _, g := range []*KG{fromGob, fromJSON} {
+		assert.Equal([]string{DefaultGraph, "project-a"}, g.ListGraphs())
+		assert.Len(g.FindTriples("Python", "", "", true), 2)
+		assert.Len(g.FindTriples("Python", "", "", true, "project-a"), 1)
+	}
+}
@@ -10,12 +10,38 @@ import (
 // It is safe for concurrent use as all operations are protected by a mutex.
 type KG struct {
 	SystemPrompt string
+	// PruneOrphans makes RemoveTriple and RemoveEntity also delete the entities
+	// left without any predicate. It is not persisted with the graph.
+	PruneOrphans bool
+	// HistoryLimit is the number of revisions kept in the history, DefaultHistoryLimit if zero;
+	// changes are not recorded if it is negative. It is not persisted with the graph.
+	HistoryLimit int
 	nodes        map[int64]*Node
-	from         map[int64]map[int64]*Predicate
-	to           map[int64]map[int64]*Predicate
-
-	currentID int64
-	mu        sync.RWMutex // protects concurrent access to the graph
+	from         map[int64]map[int64][]*Predicate
+	to           map[int64]map[int64][]*Predicate
+
+	lexicalIndex   map[string]map[int64]*Node      // exact lexical value -> nodes
+	foldedIndex    map[string]map[int64]*Node      // case-folded lexical value -> nodes
+	literalIndex   map[string]map[int64]*Node      // case-folded literal value -> literal nodes
+	predicateIndex map[string]map[int64]*Predicate // case-folded predicate subject -> predicates
+	keyIndex       map[string]*Node                // entity key -> node
+	pending        map[int64]*Node                 // nodes added through the gonum API, not indexed yet
+
+	schema *Schema // active ontology, nil if the graph has none
+	rules  []Rule  // rules inferring triples from the others
+
+	inferred      []*Predicate // triples inferred by the rules, valid until the triples or the rules change
+	inferredValid bool         // whether inferred is up to date
+	inferring     sync.Mutex   // protects inferred, which is computed under a read lock
+
+	declarations map[string]*PredicateDeclaration // case-folded predicate name -> declared properties
+	history      []Revision                       // recorded changes of the graph, oldest first
+	journal      *journal                         // changes of the modification being recorded, nil if none
+
+	currentID     int64
+	currentLineID int64
+	mu            sync.RWMutex // protects concurrent access to the graph
+	recording     sync.Mutex   // serializes the modifications recorded by RecordChanges
 }
 
 // NewKG creates and initializes a new empty knowledge graph.
@@ -24,15 +50,22 @@ func NewKG(systemPrompt string) *KG {
 	return &KG{
 		SystemPrompt: systemPrompt,
 		nodes:        make(map[int64]*Node),
-		from:         make(map[int64]map[int64]*Predicate),
-		to:           make(map[int64]map[int64]*Predicate),
+		from:         make(map[int64]map[int64][]*Predicate),
+		to:           make(map[int64]map[int64][]*Predicate),
+
+		lexicalIndex:   make(map[string]map[int64]*Node),
+		foldedIndex:    make(map[string]map[int64]*Node),
+		literalIndex:   make(map[string]map[int64]*Node),
+		predicateIndex: make(map[string]map[int64]*Predicate),
+		keyIndex:       make(map[string]*Node),
+		pending:        make(map[int64]*Node),
 	}
 }
 
 // Node returns the node with the given ID if it exists
 // in the graph, and nil otherwise.
 func (kg *KG) Node(id int64) graph.Node {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 	return kg.nodes[id]
 }
@@ -72,11 +105,46 @@ func (n *NodeList) Node() graph.Node {
 	return n.nodes[n.pos]
 }
 
+// LineList implements the graph.Lines interface
+type LineList struct {
+	lines []*Predicate
+	pos   int
+}
+
+// NewLineList creates a new LineList from the provided slice of predicates.
+// It initializes the position to -1, so Next() must be called before the first Line() access.
+func NewLineList(lines []*Predicate) *LineList {
+	return &LineList{
+		lines: lines,
+		pos:   -1,
+	}
+}
+
+func (l *LineList) Len() int {
+	return len(l.lines)
+}
+
+func (l *LineList) Next() bool {
+	l.pos++
+	return l.pos < len(l.lines)
+}
+
+func (l *LineList) Reset() {
+	l.pos = -1
+}
+
+func (l *LineList) Line() graph.Line {
+	if l.pos >= len(l.lines) || l.pos < 0 {
+		return nil
+	}
+	return l.lines[l.pos]
+}
+
 // Nodes returns all the nodes in the graph.
 //
 // Nodes must not return nil.
 func (kg *KG) Nodes() graph.Nodes {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
 	if len(kg.nodes) == 0 {
@@ -95,7 +163,7 @@ func (kg *KG) Nodes() graph.Nodes {
 //
 // From must not return nil.
 func (kg *KG) From(id int64) graph.Nodes {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
 	if kg.from[id] == nil {
@@ -114,58 +182,73 @@ func (kg *KG) From(id int64) graph.Nodes {
 // HasEdgeBetween returns whether an edge exists between
 // nodes with IDs xid and yid without considering direction.
 func (kg *KG) HasEdgeBetween(xid int64, yid int64) bool {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	// Check if there's an edge from xid to yid
-	if _, ok := kg.from[xid]; ok {
-		if _, ok := kg.from[xid][yid]; ok {
-			return true
-		}
-	}
-
-	// Check if there's an edge from yid to xid
-	if _, ok := kg.from[yid]; ok {
-		if _, ok := kg.from[yid][xid]; ok {
-			return true
-		}
-	}
-
-	return false
+	// Check if there's an edge from xid to yid or from yid to xid
+	return len(kg.from[xid][yid]) > 0 || len(kg.from[yid][xid]) > 0
 }
 
 // Edge returns the edge from u to v, with IDs uid and vid,
 // if such an edge exists and nil otherwise. The node v
 // must be directly reachable from u as defined by the
 // From method.
+// As the graph is a multigraph, Edge returns the first predicate
+// linking u to v; use Lines to get all of them.
 func (kg *KG) Edge(uid int64, vid int64) graph.Edge {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	if _, ok := kg.from[uid]; !ok {
+	lines := kg.from[uid][vid]
+	if len(lines) == 0 {
 		return nil
 	}
 
-	pred, ok := kg.from[uid][vid]
-	if !ok {
-		return nil
+	return lines[0]
+}
+
+// Lines returns the lines from u to v, with IDs uid and
+// vid, if any such lines exist. The node v must be
+// directly reachable from u as defined by the From method.
+// This method satisfies the graph.Multigraph interface.
+//
+// Lines must not return nil.
+func (kg *KG) Lines(uid int64, vid int64) graph.Lines {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	lines := kg.from[uid][vid]
+	if len(lines) == 0 {
+		return NewLineList(nil)
 	}
 
-	return pred
+	// Copy the slice so the iterator is not affected by later mutations
+	return NewLineList(append([]*Predicate(nil), lines...))
+}
+
+// LinesBetween returns the lines between nodes x and y
+// with IDs xid and yid without considering direction.
+// This method satisfies the graph.UndirectedMultigraph interface.
+//
+// LinesBetween must not return nil.
+func (kg *KG) LinesBetween(xid int64, yid int64) graph.Lines {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	lines := append([]*Predicate(nil), kg.from[xid][yid]...)
+	if xid != yid {
+		lines = append(lines, kg.from[yid][xid]...)
+	}
+	return NewLineList(lines)
 }
 
 // HasEdgeFromTo returns whether an edge exists
 // in the graph from u to v with IDs uid and vid.
 func (kg *KG) HasEdgeFromTo(uid int64, vid int64) bool {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
-	if _, ok := kg.from[uid]; !ok {
-		return false
-	}
-
-	_, ok := kg.from[uid][vid]
-	return ok
+	return len(kg.from[uid][vid]) > 0
 }
 
 // To returns all nodes that can reach directly
@@ -173,7 +256,7 @@ func (kg *KG) HasEdgeFromTo(uid int64, vid int64) bool {
 //
 // To must not return nil.
 func (kg *KG) To(id int64) graph.Nodes {
-	kg.mu.RLock()
+	kg.readLock()
 	defer kg.mu.RUnlock()
 
 	if kg.to[id] == nil {
@@ -0,0 +1,126 @@
+package kg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Datatype is the type of a literal value stored as the object of a triple.
+type Datatype string
+
+// The datatypes supported for literal values.
+const (
+	DatatypeString     Datatype = "string"
+	DatatypeLangString Datatype = "langString"
+	DatatypeInteger    Datatype = "integer"
+	DatatypeDecimal    Datatype = "decimal"
+	DatatypeDate       Datatype = "date"
+	DatatypeDateTime   Datatype = "dateTime"
+	DatatypeBoolean    Datatype = "boolean"
+)
+
+// dateLayout is the layout of date literals.
+const dateLayout = "2006-01-02"
+
+// datatypes lists the supported datatypes by their lower-cased name.
+var datatypes = map[string]Datatype{
+	"string":     DatatypeString,
+	"langstring": DatatypeLangString,
+	"integer":    DatatypeInteger,
+	"decimal":    DatatypeDecimal,
+	"date":       DatatypeDate,
+	"datetime":   DatatypeDateTime,
+	"boolean":    DatatypeBoolean,
+}
+
+// ParseDatatype returns the datatype with the given name.
+// The comparison is case-insensitive and an optional "xsd:" prefix is accepted.
+// An empty name or "entity" denote an entity object and return the empty Datatype.
+func ParseDatatype(name string) (Datatype, error) {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "xsd:")
+	if name == "" || name == "entity" {
+		return "", nil
+	}
+	datatype, ok := datatypes[name]
+	if !ok {
+		return "", fmt.Errorf("unknown datatype %q", name)
+	}
+	return datatype, nil
+}
+
+// Literal is a typed value used as the object of a triple.
+// Literals are stored as nodes with a Datatype. Unlike entities, they are never
+// shared between triples and they are not returned by FindNode or ListNodes.
+type Literal struct {
+	Value    string
+	Datatype Datatype
+	Language string
+}
+
+// NewLiteral validates the value against the datatype and returns the literal
+// with its value in canonical form.
+// A language tag turns a string into a language-tagged string.
+func NewLiteral(value string, datatype Datatype, language string) (Literal, error) {
+	if datatype == DatatypeString && language != "" {
+		datatype = DatatypeLangString
+	}
+	if language != "" && datatype != DatatypeLangString {
+		return Literal{}, fmt.Errorf("a language tag is only allowed on string literals, not on %s", datatype)
+	}
+
+	canonical := value
+	switch datatype {
+	case DatatypeString:
+	case DatatypeLangString:
+		if language == "" {
+			return Literal{}, fmt.Errorf("a %s literal requires a language tag", datatype)
+		}
+	case DatatypeInteger:
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return Literal{}, fmt.Errorf("invalid %s literal %q", datatype, value)
+		}
+		canonical = strconv.FormatInt(i, 10)
+	case DatatypeDecimal:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return Literal{}, fmt.Errorf("invalid %s literal %q", datatype, value)
+		}
+		canonical = strconv.FormatFloat(f, 'f', -1, 64)
+	case DatatypeDate:
+		d, err := time.Parse(dateLayout, strings.TrimSpace(value))
+		if err != nil {
+			return Literal{}, fmt.Errorf("invalid %s literal %q, expected YYYY-MM-DD", datatype, value)
+		}
+		canonical = d.Format(dateLayout)
+	case DatatypeDateTime:
+		dt, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+		if err != nil {
+			return Literal{}, fmt.Errorf("invalid %s literal %q, expected RFC 3339", datatype, value)
+		}
+		canonical = dt.Format(time.RFC3339)
+	case DatatypeBoolean:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return Literal{}, fmt.Errorf("invalid %s literal %q", datatype, value)
+		}
+		canonical = strconv.FormatBool(b)
+	default:
+		return Literal{}, fmt.Errorf("unknown datatype %q", datatype)
+	}
+
+	return Literal{
+		Value:    canonical,
+		Datatype: datatype,
+		Language: strings.ToLower(language),
+	}, nil
+}
+
+// matchesNode returns true if the node holds the same literal value.
+func (literal Literal) matchesNode(node *Node) bool {
+	return node.Datatype == literal.Datatype &&
+		node.Lexical == literal.Value &&
+		node.Language == literal.Language
+}
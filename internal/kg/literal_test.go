@@ -0,0 +1,128 @@
+package kg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLiteral(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		datatype Datatype
+		language string
+		expected Literal
+		wantErr  bool
+	}{
+		{name: "string", value: "hello", datatype: DatatypeString, expected: Literal{Value: "hello", Datatype: DatatypeString}},
+		{name: "language-tagged string", value: "Bonjour", datatype: DatatypeString, language: "FR", expected: Literal{Value: "Bonjour", Datatype: DatatypeLangString, Language: "fr"}},
+		{name: "language-tagged string without tag", value: "Bonjour", datatype: DatatypeLangString, wantErr: true},
+		{name: "integer", value: " 1991 ", datatype: DatatypeInteger, expected: Literal{Value: "1991", Datatype: DatatypeInteger}},
+		{name: "invalid integer", value: "nineteen", datatype: DatatypeInteger, wantErr: true},
+		{name: "decimal", value: "3.140", datatype: DatatypeDecimal, expected: Literal{Value: "3.14", Datatype: DatatypeDecimal}},
+		{name: "date", value: "1991-02-20", datatype: DatatypeDate, expected: Literal{Value: "1991-02-20", Datatype: DatatypeDate}},
+		{name: "invalid date", value: "20/02/1991", datatype: DatatypeDate, wantErr: true},
+		{name: "dateTime", value: "2024-05-01T10:00:00Z", datatype: DatatypeDateTime, expected: Literal{Value: "2024-05-01T10:00:00Z", Datatype: DatatypeDateTime}},
+		{name: "boolean", value: "TRUE", datatype: DatatypeBoolean, expected: Literal{Value: "true", Datatype: DatatypeBoolean}},
+		{name: "language on integer", value: "1", datatype: DatatypeInteger, language: "en", wantErr: true},
+		{name: "unknown datatype", value: "1", datatype: "float", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			literal, err := NewLiteral(tc.value, tc.datatype, tc.language)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, literal)
+		})
+	}
+}
+
+func TestParseDatatype(t *testing.T) {
+	assert := assert.New(t)
+
+	for name, expected := range map[string]Datatype{
+		"":             "",
+		"entity":       "",
+		"integer":      DatatypeInteger,
+		"xsd:dateTime": DatatypeDateTime,
+		"Boolean":      DatatypeBoolean,
+	} {
+		datatype, err := ParseDatatype(name)
+		assert.NoError(err, "ParseDatatype(%q) should not fail", name)
+		assert.Equal(expected, datatype, "ParseDatatype(%q)", name)
+	}
+
+	_, err := ParseDatatype("float")
+	assert.Error(err, "Unknown datatypes should be rejected")
+}
+
+func TestInsertLiteral(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, false))
+	assert.NoError(kg.Insert(Triple{Subject: "Linux", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, false))
+	assert.NoError(kg.InsertTriple("1991", "is_a", "Year", false))
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "label", Object: "Python", Language: "en"}, false))
+
+	// Inserting the same literal twice is a no-op
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, false))
+	assert.Len(kg.FindTriples("Python", "first_released", "", false), 1)
+
+	// Invalid literals are rejected
+	assert.Error(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "early nineties", ObjectType: DatatypeInteger}, false))
+
+	// Literals are not shared between triples nor with the entity of the same name
+	year := kg.FindNode("1991", false)
+	assert.NotNil(year)
+	assert.False(year.IsLiteral())
+	assert.Equal([][3]string{{"1991", "is_a", "Year"}}, kg.DescribeEntity("1991", false), "Literals should not be described as the entity")
+	assert.NotContains(kg.ListNodes(), "Python@en")
+	assert.Len(kg.ListNodes(), 4, "Only entities should be listed")
+	assert.Len(kg.FindTriples("", "", "1991", false), 2, "Literal objects should be matched by value")
+
+	predicates, err := kg.PredicatesFromTo("Python", "1991", false)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal(`"1991"^^integer`, predicates[0].T.(*Node).String())
+	predicates, err = kg.PredicatesFromTo("Python", "Python", false)
+	assert.NoError(err)
+	assert.Equal(`"Python"@en`, predicates[0].T.(*Node).String())
+
+	// Round trips should keep the datatypes
+	var gobBuf bytes.Buffer
+	assert.NoError(WriteTo(&gobBuf, kg))
+	fromGob, err := ReadFrom(&gobBuf)
+	assert.NoError(err)
+	predicates, err = fromGob.PredicatesFromTo("Linux", "1991", false)
+	assert.NoError(err)
+	assert.Equal(DatatypeInteger, predicates[0].T.(*Node).Datatype)
+	predicates, err = fromGob.PredicatesFromTo("Python", "Python", false)
+	assert.NoError(err)
+	assert.Equal("en", predicates[0].T.(*Node).Language)
+
+	var jsonBuf bytes.Buffer
+	assert.NoError(SaveToJSON(&jsonBuf, kg))
+	fromJSON, err := ReadFromJSON(&jsonBuf)
+	assert.NoError(err)
+	predicates, err = fromJSON.PredicatesFromTo("Linux", "1991", false)
+	assert.NoError(err)
+	assert.Equal(DatatypeInteger, predicates[0].T.(*Node).Datatype)
+	assert.Nil(fromJSON.FindNode("Python@en", false))
+
+	// Removing the triple removes its literal value
+	predicates, err = kg.PredicatesFromTo("Python", "1991", false)
+	assert.NoError(err)
+	literalID := predicates[0].T.ID()
+	removed, err := kg.RemoveTriple("Python", "first_released", "1991", false)
+	assert.NoError(err)
+	assert.True(removed)
+	assert.Nil(kg.Node(literalID), "The literal node should be removed with its triple")
+	assert.NotNil(kg.FindNode("1991", false), "The entity should be kept")
+}
@@ -0,0 +1,124 @@
+package kg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata records the provenance of a triple and the period during which the fact it states holds.
+type Metadata struct {
+	Created    time.Time // Time the triple was inserted
+	Modified   time.Time // Time the triple or its metadata was last changed
+	Source     string    // Free-form origin of the fact, such as a document URI or a conversation id
+	Agent      string    // Name of the agent that asserted the fact
+	Confidence float64   // Confidence in the fact between 0 and 1, zero if unknown
+	ValidFrom  time.Time // Time from which the fact holds, since always if zero
+	ValidTo    time.Time // Time from which the fact no longer holds, still holding if zero
+}
+
+// validateValidity checks that a validity period does not end before it starts.
+func validateValidity(validFrom, validTo time.Time) error {
+	if !validFrom.IsZero() && !validTo.IsZero() && !validTo.After(validFrom) {
+		return fmt.Errorf("invalid validity: %s is not after %s", validTo.UTC().Format(time.RFC3339), validFrom.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ValidAt reports whether the fact holds at the given time: the time is within [ValidFrom, ValidTo),
+// a zero bound being unbounded.
+func (metadata Metadata) ValidAt(t time.Time) bool {
+	return (metadata.ValidFrom.IsZero() || !t.Before(metadata.ValidFrom)) &&
+		(metadata.ValidTo.IsZero() || t.Before(metadata.ValidTo))
+}
+
+// overlaps reports whether the validity periods of two facts have a moment in common.
+func (metadata Metadata) overlaps(other Metadata) bool {
+	return (metadata.ValidTo.IsZero() || other.ValidFrom.IsZero() || other.ValidFrom.Before(metadata.ValidTo)) &&
+		(other.ValidTo.IsZero() || metadata.ValidFrom.IsZero() || metadata.ValidFrom.Before(other.ValidTo))
+}
+
+// Validity returns the validity period of the fact, such as "valid from 2020-01-01T00:00:00Z to 2022-06-30T00:00:00Z",
+// or an empty string if the fact always holds.
+func (metadata Metadata) Validity() string {
+	var bounds []string
+	if !metadata.ValidFrom.IsZero() {
+		bounds = append(bounds, "from "+metadata.ValidFrom.UTC().Format(time.RFC3339))
+	}
+	if !metadata.ValidTo.IsZero() {
+		bounds = append(bounds, "to "+metadata.ValidTo.UTC().Format(time.RFC3339))
+	}
+	if len(bounds) == 0 {
+		return ""
+	}
+	return "valid " + strings.Join(bounds, " ")
+}
+
+// validateConfidence checks that a confidence score is between 0 and 1.
+func validateConfidence(confidence float64) error {
+	if confidence < 0 || confidence > 1 {
+		return fmt.Errorf("invalid confidence %v: expected a value between 0 and 1", confidence)
+	}
+	return nil
+}
+
+// update sets the provenance and validity fields given in the triple and records the modification time.
+// Empty fields of the triple leave the current values unchanged.
+func (metadata *Metadata) update(triple Triple, now time.Time) {
+	changed := false
+	if triple.Source != "" && triple.Source != metadata.Source {
+		metadata.Source = triple.Source
+		changed = true
+	}
+	if triple.Agent != "" && triple.Agent != metadata.Agent {
+		metadata.Agent = triple.Agent
+		changed = true
+	}
+	if triple.Confidence != 0 && triple.Confidence != metadata.Confidence {
+		metadata.Confidence = triple.Confidence
+		changed = true
+	}
+	if !triple.ValidFrom.IsZero() && !triple.ValidFrom.Equal(metadata.ValidFrom) {
+		metadata.ValidFrom = triple.ValidFrom
+		changed = true
+	}
+	if !triple.ValidTo.IsZero() && !triple.ValidTo.Equal(metadata.ValidTo) {
+		metadata.ValidTo = triple.ValidTo
+		changed = true
+	}
+	if changed {
+		metadata.Modified = now
+	}
+}
+
+// restates reports whether inserting the triple updates the fact recorded with this metadata,
+// rather than recording a new period of validity: the fact still holds, or is given the same validity.
+func (metadata Metadata) restates(triple Triple) bool {
+	return metadata.ValidTo.IsZero() ||
+		(metadata.ValidFrom.Equal(triple.ValidFrom) && metadata.ValidTo.Equal(triple.ValidTo))
+}
+
+// String returns the known provenance fields of the metadata, such as
+// "created 2024-05-01T10:00:00Z, source chat-42, agent assistant, confidence 0.9".
+// The validity period is given by Validity.
+// It returns an empty string if no field is set.
+func (metadata Metadata) String() string {
+	var fields []string
+	if !metadata.Created.IsZero() {
+		fields = append(fields, "created "+metadata.Created.UTC().Format(time.RFC3339))
+	}
+	if !metadata.Modified.IsZero() && !metadata.Modified.Equal(metadata.Created) {
+		fields = append(fields, "modified "+metadata.Modified.UTC().Format(time.RFC3339))
+	}
+	if metadata.Source != "" {
+		fields = append(fields, "source "+metadata.Source)
+	}
+	if metadata.Agent != "" {
+		fields = append(fields, "agent "+metadata.Agent)
+	}
+	if metadata.Confidence != 0 {
+		fields = append(fields, "confidence "+strconv.FormatFloat(metadata.Confidence, 'g', -1, 64))
+	}
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,67 @@
+package kg
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertWithMetadata(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	before := time.Now()
+	assert.NoError(kg.Insert(Triple{
+		Subject:    "Python",
+		Predicate:  "created_by",
+		Object:     "Guido",
+		Source:     "chat-42",
+		Agent:      "assistant",
+		Confidence: 0.8,
+	}, true))
+
+	pred := kg.FindPredicate("created_by", true)
+	assert.NotNil(pred)
+	assert.Equal("chat-42", pred.Metadata.Source)
+	assert.Equal("assistant", pred.Metadata.Agent)
+	assert.Equal(0.8, pred.Metadata.Confidence)
+	assert.False(pred.Metadata.Created.Before(before), "The creation time should be recorded")
+	assert.Equal(pred.Metadata.Created, pred.Metadata.Modified)
+
+	// Inserting the triple again updates the provenance fields that are set
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "created_by", Object: "Guido", Confidence: 0.9}, true))
+	assert.Len(kg.FindTriples("Python", "created_by", "Guido", true), 1)
+	assert.Equal("chat-42", pred.Metadata.Source)
+	assert.Equal(0.9, pred.Metadata.Confidence)
+	assert.False(pred.Metadata.Modified.Before(pred.Metadata.Created))
+
+	// The confidence must be between 0 and 1
+	assert.Error(kg.Insert(Triple{Subject: "Python", Predicate: "created_by", Object: "Guido", Confidence: 1.5}, true))
+
+	// The metadata survives serialization
+	var buf bytes.Buffer
+	assert.NoError(WriteTo(&buf, kg))
+	restored, err := ReadFrom(&buf)
+	assert.NoError(err)
+	restoredPred := restored.FindPredicate("created_by", true)
+	assert.NotNil(restoredPred)
+	assert.Equal(pred.Metadata.Source, restoredPred.Metadata.Source)
+	assert.Equal(pred.Metadata.Confidence, restoredPred.Metadata.Confidence)
+	assert.True(pred.Metadata.Created.Equal(restoredPred.Metadata.Created))
+}
+
+func TestMetadataString(t *testing.T) {
+	assert := assert.New(t)
+
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	metadata := Metadata{Created: created, Modified: created, Source: "chat-42", Confidence: 0.9}
+	assert.Equal("created 2024-05-01T10:00:00Z, source chat-42, confidence 0.9", metadata.String())
+
+	metadata.Modified = created.Add(time.Hour)
+	metadata.Agent = "assistant"
+	assert.Equal("created 2024-05-01T10:00:00Z, modified 2024-05-01T11:00:00Z, source chat-42, agent assistant, confidence 0.9", metadata.String())
+
+	assert.Empty(Metadata{}.String())
+}
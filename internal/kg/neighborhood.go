@@ -0,0 +1,114 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Direction tells which triples a traversal follows from an entity.
+type Direction string
+
+const (
+	DirectionOutgoing Direction = "outgoing" // Triples whose subject is the entity, to their object
+	DirectionIncoming Direction = "incoming" // Triples whose object is the entity, to their subject
+	DirectionBoth     Direction = "both"     // Triples in both directions
+)
+
+// NeighborhoodOptions restricts the triples followed to build the neighborhood of an entity.
+type NeighborhoodOptions struct {
+	Direction Direction // Triples followed from each entity, DirectionBoth if empty
+	Graphs    []string  // Names of the graphs whose triples are followed and returned, all graphs if empty
+	MaxNodes  int       // Maximum number of entities in the neighborhood, the closest being kept; unlimited if zero
+}
+
+// Subgraph is a part of the knowledge graph: a set of entities and the triples between them.
+type Subgraph struct {
+	Entities  []*Node       // Entities of the subgraph, by distance from the center, then by label
+	Distance  map[int64]int // Number of hops from the center to each entity, by node ID
+	Triples   []*Predicate  // Triples between the entities and with their literal values, sorted by subject, predicate and object
+	Truncated bool          // Whether entities within reach were left out to respect MaxNodes
+}
+
+// Neighborhood returns the subgraph induced by the entities within the given number of hops of the entity:
+// these entities, the triples between them and the triples giving them literal values.
+// Only the triples whose predicate is one of predicateFilter are followed and returned,
+// unless predicateFilter is empty.
+// It returns an error if the entity does not exist or is ambiguous, or if the options are invalid.
+// The caseSensitiveSearch parameter determines if the entity lookup and the predicate filter are case-sensitive.
+func (kg *KG) Neighborhood(entity string, hops int, predicateFilter []string, options NeighborhoodOptions, caseSensitiveSearch bool) (*Subgraph, error) {
+	if hops < 0 {
+		return nil, fmt.Errorf("invalid number of hops %d: it cannot be negative", hops)
+	}
+	if options.MaxNodes < 0 {
+		return nil, fmt.Errorf("invalid maximum number of entities %d: it cannot be negative", options.MaxNodes)
+	}
+	direction := options.Direction
+	switch direction {
+	case "":
+		direction = DirectionBoth
+	case DirectionOutgoing, DirectionIncoming, DirectionBoth:
+	default:
+		return nil, fmt.Errorf("unknown direction %q: expected outgoing, incoming or both", direction)
+	}
+
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	center, err := kg.resolveEntity(entity, caseSensitiveSearch)
+	if err != nil {
+		return nil, err
+	}
+	if center == nil {
+		return nil, fmt.Errorf("entity %q not found", entity)
+	}
+
+	// Only the filtered predicates are followed and returned
+	selected := predicateSelector(predicateFilter, options.Graphs, caseSensitiveSearch)
+
+	// Explore the entities in breadth-first order, the hops leaving each entity being sorted
+	allHops := kg.traversableHops(PathOptions{Undirected: true, Graphs: options.Graphs})
+	subgraph := &Subgraph{
+		Entities: []*Node{center},
+		Distance: map[int64]int{center.ID(): 0},
+	}
+	frontier := []*Node{center}
+	for distance := 1; distance <= hops && len(frontier) > 0; distance++ {
+		var next []*Node
+		for _, node := range frontier {
+			for _, hop := range allHops[node.ID()] {
+				if _, seen := subgraph.Distance[hop.To.ID()]; seen || !selected(hop.Triple) ||
+					(direction == DirectionOutgoing && hop.Reversed) || (direction == DirectionIncoming && !hop.Reversed) {
+					continue
+				}
+				if options.MaxNodes > 0 && len(subgraph.Entities) >= options.MaxNodes {
+					subgraph.Truncated = true
+					break
+				}
+				subgraph.Distance[hop.To.ID()] = distance
+				subgraph.Entities = append(subgraph.Entities, hop.To)
+				next = append(next, hop.To)
+			}
+		}
+		sort.SliceStable(next, func(i, j int) bool {
+			return compareNodes(next[i], next[j]) < 0
+		})
+		copy(subgraph.Entities[len(subgraph.Entities)-len(next):], next)
+		frontier = next
+	}
+
+	// Keep the triples between the entities, and their literal values
+	for _, pred := range kg.allPredicates() {
+		if pred.Subject == "" || !selected(pred) {
+			continue
+		}
+		if _, inside := subgraph.Distance[pred.F.ID()]; !inside {
+			continue
+		}
+		if _, inside := subgraph.Distance[pred.T.ID()]; inside || pred.T.(*Node).IsLiteral() {
+			subgraph.Triples = append(subgraph.Triples, pred)
+		}
+	}
+	sortTriples(subgraph.Triples, QueryOptions{})
+
+	return subgraph, nil
+}
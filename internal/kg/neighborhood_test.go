@@ -0,0 +1,83 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeighborhood(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	for _, triple := range [][3]string{
+		{"Python", "created_by", "Guido van Rossum"},
+		{"Python", "influenced", "Go"},
+		{"ABC", "influenced", "Python"},
+		{"Go", "created_by", "Rob Pike"},
+		{"Guido van Rossum", "works_for", "Microsoft"},
+		{"Rob Pike", "works_for", "Google"},
+	} {
+		assert.NoError(kg.InsertTriple(triple[0], triple[1], triple[2], true))
+	}
+	assert.NoError(kg.Insert(Triple{Subject: "Python", Predicate: "first_released", Object: "1991", ObjectType: DatatypeInteger}, true))
+	assert.NoError(kg.InsertTriple("Go", "influenced", "Python", true, "draft"))
+
+	labels := func(nodes []*Node) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Lexical)
+		}
+		return result
+	}
+	statements := func(predicates []*Predicate) [][3]string {
+		var result [][3]string
+		for _, pred := range predicates {
+			result = appendTriple(result, pred)
+		}
+		return result
+	}
+
+	// One hop in both directions
+	subgraph, err := kg.Neighborhood("python", 1, nil, NeighborhoodOptions{}, false)
+	assert.NoError(err)
+	assert.Equal([]string{"Python", "ABC", "Go", "Guido van Rossum"}, labels(subgraph.Entities))
+	assert.Equal(1, subgraph.Distance[kg.FindNode("Go", true).ID()])
+	assert.Equal([][3]string{
+		{"ABC", "influenced", "Python"},
+		{"Go", "influenced", "Python"},
+		{"Python", "created_by", "Guido van Rossum"},
+		{"Python", "first_released", "1991"},
+		{"Python", "influenced", "Go"},
+	}, statements(subgraph.Triples))
+	assert.False(subgraph.Truncated)
+
+	// Two hops along the outgoing triples of the default graph only
+	subgraph, err = kg.Neighborhood("Python", 2, nil, NeighborhoodOptions{Direction: DirectionOutgoing, Graphs: []string{DefaultGraph}}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Python", "Go", "Guido van Rossum", "Microsoft", "Rob Pike"}, labels(subgraph.Entities))
+	assert.Equal(2, subgraph.Distance[kg.FindNode("Rob Pike", true).ID()])
+
+	// Filtered predicates
+	subgraph, err = kg.Neighborhood("Rob Pike", 3, []string{"created_by", "INFLUENCED"}, NeighborhoodOptions{Direction: DirectionIncoming}, false)
+	assert.NoError(err)
+	assert.Equal([]string{"Rob Pike", "Go", "Python", "ABC"}, labels(subgraph.Entities))
+	assert.Len(subgraph.Triples, 4)
+
+	// Node budget
+	subgraph, err = kg.Neighborhood("Python", 3, nil, NeighborhoodOptions{MaxNodes: 3}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Python", "ABC", "Go"}, labels(subgraph.Entities))
+	assert.True(subgraph.Truncated)
+
+	subgraph, err = kg.Neighborhood("Python", 0, nil, NeighborhoodOptions{}, true)
+	assert.NoError(err)
+	assert.Equal([][3]string{{"Python", "first_released", "1991"}}, statements(subgraph.Triples))
+
+	_, err = kg.Neighborhood("Python", -1, nil, NeighborhoodOptions{}, true)
+	assert.Error(err)
+	_, err = kg.Neighborhood("Python", 1, nil, NeighborhoodOptions{Direction: "sideways"}, true)
+	assert.Error(err)
+	_, err = kg.Neighborhood("Nobody", 1, nil, NeighborhoodOptions{}, true)
+	assert.Error(err)
+}
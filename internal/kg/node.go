@@ -1,10 +1,22 @@
 package kg
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Node represents a vertex in the knowledge graph.
 // Each node has a unique ID and a Lexical field that holds its string representation.
+// A node with a Datatype is a typed literal value rather than an entity; see Literal.
+// An entity may also be known under alternate labels, its aliases, and is identified by a stable Key,
+// such as an IRI, that does not change when it is relabeled.
 type Node struct {
-	Identifier int64 `json:"id"` // Using ID_ with json tag for serialization
+	Identifier int64  `json:"id"`         // Using ID_ with json tag for serialization
+	Key        string `json:",omitempty"` // Stable external identifier of an entity
 	Lexical    string
+	Datatype   Datatype `json:",omitempty"` // Datatype of a literal value, empty for entities
+	Language   string   `json:",omitempty"` // Language tag of a language-tagged string literal
+	Aliases    []string `json:",omitempty"` // Alternate labels of an entity
 }
 
 // ID returns the unique identifier of the node.
@@ -12,3 +24,58 @@ type Node struct {
 func (node *Node) ID() int64 {
 	return node.Identifier
 }
+
+// IsLiteral returns true if the node holds a typed literal value rather than an entity.
+func (node *Node) IsLiteral() bool {
+	return node.Datatype != ""
+}
+
+// matches reports whether the key, the lexical value or one of the aliases of the node is label.
+// The caseSensitiveSearch parameter determines if the comparison of the labels is case-sensitive;
+// keys are always compared exactly.
+func (node *Node) matches(label string, caseSensitiveSearch bool) bool {
+	if node.Key != "" && node.Key == label {
+		return true
+	}
+	if predicateMatches(node.Lexical, label, caseSensitiveSearch) {
+		return true
+	}
+	for _, alias := range node.Aliases {
+		if predicateMatches(alias, label, caseSensitiveSearch) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the lexical value of an entity, or the typed notation of a literal,
+// such as "1991"^^integer or "Bonjour"@fr.
+func (node *Node) String() string {
+	switch node.Datatype {
+	case "":
+		return node.Lexical
+	case DatatypeLangString:
+		return `"` + node.Lexical + `"@` + node.Language
+	default:
+		return `"` + node.Lexical + `"^^` + string(node.Datatype)
+	}
+}
+
+// generatedKeyPrefix is the prefix of the keys generated for entities inserted without a key.
+const generatedKeyPrefix = "_:e"
+
+// generatedKey returns the key generated for the entity node with the given ID.
+func generatedKey(id int64) string {
+	return fmt.Sprintf("%s%d", generatedKeyPrefix, id)
+}
+
+// validateKey checks that a user-supplied key is usable as an entity identifier.
+func validateKey(key string) error {
+	switch {
+	case strings.TrimSpace(key) != key || key == "":
+		return fmt.Errorf("invalid key %q: keys cannot be empty or start or end with spaces", key)
+	case strings.HasPrefix(key, "_:"):
+		return fmt.Errorf("invalid key %q: keys starting with _: are reserved for generated keys", key)
+	}
+	return nil
+}
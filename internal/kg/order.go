@@ -0,0 +1,145 @@
+package kg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortOrder is the order in which a query returns its results.
+type SortOrder string
+
+const (
+	// SortBySubject orders triples by subject, predicate and object. It is the default order.
+	SortBySubject SortOrder = "subject"
+	// SortByPredicate orders triples by predicate, subject and object.
+	SortByPredicate SortOrder = "predicate"
+	// SortByObject orders triples by object, subject and predicate.
+	SortByObject SortOrder = "object"
+	// SortByCreation orders triples, entities and predicates by the time they were inserted.
+	SortByCreation SortOrder = "created"
+)
+
+// QueryOptions gives the order of the results of a query and the page of results to return.
+// Labels are compared case-insensitively, and literal values of the same kind by value,
+// such as numbers numerically; ties are broken so that the order is the same on every call.
+// Entities and predicates are ordered by label, unless SortByCreation is given.
+type QueryOptions struct {
+	SortBy     SortOrder // Order of the results, SortBySubject if empty
+	Descending bool      // Whether to return the results in the reverse order
+	Offset     int       // Number of results to skip
+	Limit      int       // Maximum number of results to return, all the remaining results if zero
+}
+
+// Validate checks that the sort order is known and that the page bounds are not negative.
+func (options QueryOptions) Validate() error {
+	switch options.SortBy {
+	case "", SortBySubject, SortByPredicate, SortByObject, SortByCreation:
+	default:
+		return fmt.Errorf("unknown sort order %q: expected subject, predicate, object or created", options.SortBy)
+	}
+	if options.Offset < 0 || options.Limit < 0 {
+		return fmt.Errorf("invalid page: offset %d and limit %d cannot be negative", options.Offset, options.Limit)
+	}
+	return nil
+}
+
+// page returns the bounds of the requested page in a list of total results.
+func (options QueryOptions) page(total int) (start, end int) {
+	start = min(options.Offset, total)
+	end = total
+	if options.Limit > 0 {
+		end = min(start+options.Limit, total)
+	}
+	return start, end
+}
+
+// sortTriples sorts the predicates in the order given by the options.
+func sortTriples(predicates []*Predicate, options QueryOptions) {
+	sort.SliceStable(predicates, func(i, j int) bool {
+		c := compareTriples(predicates[i], predicates[j], options.SortBy)
+		if options.Descending {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// pageTriples sorts the predicates and returns the requested page of them.
+func pageTriples(predicates []*Predicate, options QueryOptions) []*Predicate {
+	sortTriples(predicates, options)
+	start, end := options.page(len(predicates))
+	return predicates[start:end]
+}
+
+// compareTriples compares two triples in the given order.
+// It returns a negative number if a comes first, a positive number if b comes first, and zero for the same predicate.
+func compareTriples(a, b *Predicate, order SortOrder) int {
+	subject := func() int { return compareNodes(a.F.(*Node), b.F.(*Node)) }
+	predicate := func() int { return compareLabels(a.Subject, b.Subject) }
+	object := func() int { return compareNodes(a.T.(*Node), b.T.(*Node)) }
+
+	var keys []func() int
+	switch order {
+	case SortByPredicate:
+		keys = []func() int{predicate, subject, object}
+	case SortByObject:
+		keys = []func() int{object, subject, predicate}
+	case SortByCreation:
+		created := func() int { return a.Metadata.Created.Compare(b.Metadata.Created) }
+		id := func() int { return compareOrdered(a.Identifier, b.Identifier) }
+		keys = []func() int{created, id, subject, predicate, object}
+	default:
+		keys = []func() int{subject, predicate, object}
+	}
+	keys = append(keys,
+		func() int { return strings.Compare(a.Graph, b.Graph) },
+		func() int { return compareOrdered(a.Identifier, b.Identifier) },
+		func() int { return strings.Compare(a.Rule, b.Rule) },
+	)
+
+	for _, key := range keys {
+		if c := key(); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareNodes compares two nodes by label, literal values of the same kind being compared by value.
+func compareNodes(a, b *Node) int {
+	if a.IsLiteral() && b.IsLiteral() && kindOf(a.Datatype) == kindOf(b.Datatype) && kindOf(a.Datatype) != kindString {
+		if c, err := compareValues(kindOf(a.Datatype), a.Lexical, b.Lexical); err == nil && c != 0 {
+			return c
+		}
+	}
+	if c := compareLabels(a.Lexical, b.Lexical); c != 0 {
+		return c
+	}
+	return compareOrdered(a.Identifier, b.Identifier)
+}
+
+// compareLabels compares two labels case-insensitively, then exactly.
+func compareLabels(a, b string) int {
+	if c := strings.Compare(foldKey(a), foldKey(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// pageLabels sorts labels case-insensitively, unless they are already in the order of creation,
+// and returns the requested page of them.
+func pageLabels(labels []string, options QueryOptions) []string {
+	if options.SortBy != SortByCreation {
+		sort.SliceStable(labels, func(i, j int) bool {
+			return compareLabels(labels[i], labels[j]) < 0
+		})
+	}
+	if options.Descending {
+		for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+			labels[i], labels[j] = labels[j], labels[i]
+		}
+	}
+	start, end := options.page(len(labels))
+	return labels[start:end]
+}
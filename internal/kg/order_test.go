@@ -0,0 +1,79 @@
+package kg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryOrder(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("python", "influenced", "Go", true))
+	assert.NoError(kg.InsertTriple("Go", "created_by", "Rob Pike", true))
+	assert.NoError(kg.InsertTriple("Go", "created_by", "Ken Thompson", true))
+	assert.NoError(kg.Insert(Triple{Subject: "Go", Predicate: "first_released", Object: "2009", ObjectType: DatatypeInteger}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "python", Predicate: "first_released", Object: "991", ObjectType: DatatypeInteger}, true))
+	assert.NoError(kg.InsertTriple("ABC", "influenced", "python", true))
+
+	// Default order, case-insensitive
+	expected := [][3]string{
+		{"ABC", "influenced", "python"},
+		{"Go", "created_by", "Ken Thompson"},
+		{"Go", "created_by", "Rob Pike"},
+		{"Go", "first_released", "2009"},
+		{"python", "first_released", "991"},
+		{"python", "influenced", "Go"},
+	}
+	for range 5 {
+		assert.Equal(expected, kg.FindTriples("", "", "", true))
+	}
+	assert.Equal([]string{"ABC", "Go", "Ken Thompson", "python", "Rob Pike"}, kg.ListNodes())
+	assert.Equal([]string{"created_by", "first_released", "influenced"}, kg.ListAllPredicates())
+	assert.Equal([][3]string{
+		{"ABC", "influenced", "python"},
+		{"python", "first_released", "991"},
+		{"python", "influenced", "Go"},
+	}, kg.DescribeEntity("Python", false))
+
+	// Configurable orders and pages
+	triples, total, err := kg.QueryTriples(TriplePattern{Predicate: "first_released"}, QueryOptions{SortBy: SortByObject}, true)
+	assert.NoError(err)
+	assert.Equal(2, total)
+	assert.Equal("991", triples[0].T.(*Node).Lexical, "Numbers are compared numerically")
+
+	triples, total, err = kg.QueryTriples(TriplePattern{}, QueryOptions{SortBy: SortByPredicate, Descending: true, Offset: 1, Limit: 2}, true)
+	assert.NoError(err)
+	assert.Equal(6, total)
+	assert.Len(triples, 2)
+	assert.Equal("influenced", triples[0].Subject)
+	assert.Equal("ABC", triples[0].F.(*Node).Lexical, "The whole order is reversed")
+	assert.Equal("first_released", triples[1].Subject)
+
+	triples, total, err = kg.QueryTriples(TriplePattern{}, QueryOptions{SortBy: SortByCreation, Offset: 10}, true)
+	assert.NoError(err)
+	assert.Equal(6, total)
+	assert.Empty(triples, "A page beyond the results is empty")
+
+	_, _, err = kg.QueryTriples(TriplePattern{}, QueryOptions{SortBy: "size"}, true)
+	assert.Error(err)
+	_, _, err = kg.QueryTriples(TriplePattern{}, QueryOptions{Limit: -1}, true)
+	assert.Error(err)
+
+	described, total, err := kg.DescribeEntityPage("Go", time.Time{}, QueryOptions{SortBy: SortByCreation, Limit: 2}, true)
+	assert.NoError(err)
+	assert.Equal(4, total)
+	assert.Equal([][3]string{{"python", "influenced", "Go"}, {"Go", "created_by", "Rob Pike"}}, described)
+
+	nodes, total, err := kg.ListNodesPage(QueryOptions{SortBy: SortByCreation, Descending: true, Limit: 2})
+	assert.NoError(err)
+	assert.Equal(5, total)
+	assert.Equal([]string{"ABC", "Ken Thompson"}, nodes)
+
+	predicates, total, err := kg.ListAllPredicatesPage(QueryOptions{SortBy: SortByCreation})
+	assert.NoError(err)
+	assert.Equal(3, total)
+	assert.Equal([]string{"influenced", "created_by", "first_released"}, predicates)
+}
@@ -0,0 +1,297 @@
+package kg
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// Hop is a step of a path through the knowledge graph: a triple linking two entities,
+// traversed from its subject to its object or, for an undirected path, from its object to its subject.
+type Hop struct {
+	From     *Node      // Entity the hop leaves
+	To       *Node      // Entity the hop reaches
+	Triple   *Predicate // Triple traversed
+	Reversed bool       // Whether the triple is traversed from its object to its subject
+}
+
+// Label returns the predicate of the hop as it reads from From to To: the predicate of the triple,
+// or its inverse if the triple is traversed in reverse, as in ^parent_of or a declared inverse such as child_of.
+func (hop Hop) Label() string {
+	if hop.Reversed {
+		return hop.Triple.inverseSubject()
+	}
+	return hop.Triple.Subject
+}
+
+// Path is a sequence of hops, each hop leaving the entity the previous one reached.
+type Path []Hop
+
+// Labels returns the labels of the entities and predicates along the path,
+// such as [Alice, works_for, Acme, ^works_for, Bob].
+func (p Path) Labels() []string {
+	if len(p) == 0 {
+		return nil
+	}
+	labels := []string{p[0].From.Lexical}
+	for _, hop := range p {
+		labels = append(labels, hop.Label(), hop.To.Lexical)
+	}
+	return labels
+}
+
+// String returns the path with the direction of each triple, such as "Alice -[works_for]-> Acme <-[works_for]- Bob".
+func (p Path) String() string {
+	if len(p) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString(p[0].From.Lexical)
+	for _, hop := range p {
+		if hop.Reversed {
+			builder.WriteString(" <-[" + hop.Triple.Subject + "]- ")
+		} else {
+			builder.WriteString(" -[" + hop.Triple.Subject + "]-> ")
+		}
+		builder.WriteString(hop.To.Lexical)
+	}
+	return builder.String()
+}
+
+// PathOptions restricts the triples a path may traverse.
+type PathOptions struct {
+	Undirected bool     // Whether triples may also be traversed from their object to their subject
+	Graphs     []string // Names of the graphs whose triples may be traversed, all graphs if empty
+	MaxPaths   int      // Maximum number of paths returned by AllPaths, all of them if zero
+}
+
+// ShortestPath returns a path with the fewest hops from the entity from to the entity to,
+// traversing only triples between entities. Among the shortest paths, the first one in the order
+// of the labels of its entities and predicates is returned, so that the result is the same on every call.
+// It returns a nil path if to cannot be reached from from, and an error if either entity does not exist
+// or is ambiguous, or if they are the same entity.
+// The caseSensitiveSearch parameter determines if the entity lookups are case-sensitive.
+func (kg *KG) ShortestPath(from, to string, options PathOptions, caseSensitiveSearch bool) (Path, error) {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	fromNode, toNode, err := kg.pathEnds(from, to, caseSensitiveSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	hops := kg.traversableHops(options)
+	distances := distancesTo(toNode, hopGraph(hops))
+	if _, reachable := distances[fromNode.ID()]; !reachable {
+		return nil, nil
+	}
+
+	// Take the first hop getting closer to to at each step: the hops leaving an entity are sorted
+	var result Path
+	for node := fromNode; node != toNode; node = result[len(result)-1].To {
+		for _, hop := range hops[node.ID()] {
+			if distance, reachable := distances[hop.To.ID()]; reachable && distance == distances[node.ID()]-1 {
+				result = append(result, hop)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// AllPaths returns the paths of at most maxDepth hops from the entity from to the entity to
+// that do not go through the same entity twice, traversing only triples between entities.
+// Paths through different triples linking the same entities are different paths.
+// The paths are sorted by number of hops, then by the labels of their entities and predicates.
+// If options.MaxPaths is set, only the shortest paths are returned, at most MaxPaths of them;
+// which of the paths of the same length are kept at that limit is unspecified.
+// It returns an error if either entity does not exist or is ambiguous, if they are the same entity,
+// or if maxDepth is not positive.
+// The caseSensitiveSearch parameter determines if the entity lookups are case-sensitive.
+func (kg *KG) AllPaths(from, to string, maxDepth int, options PathOptions, caseSensitiveSearch bool) ([]Path, error) {
+	if maxDepth <= 0 {
+		return nil, fmt.Errorf("invalid maximum depth %d: paths have at least one hop", maxDepth)
+	}
+
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	fromNode, toNode, err := kg.pathEnds(from, to, caseSensitiveSearch)
+	if err != nil {
+		return nil, err
+	}
+	hops := kg.traversableHops(options)
+	g := hopGraph(hops)
+	distance, reachable := distancesTo(toNode, g)[fromNode.ID()]
+	if !reachable || distance > maxDepth {
+		return nil, nil
+	}
+
+	// Find the sequences of entities within maxDepth hops, shortest first,
+	// then the paths along each sequence through the triples linking its entities
+	k := -1
+	if options.MaxPaths > 0 {
+		k = options.MaxPaths
+	}
+	var paths []Path
+	for _, nodes := range path.YenKShortestPaths(g, k, float64(maxDepth-distance), simple.Node(fromNode.ID()), simple.Node(toNode.ID())) {
+		paths = append(paths, pathsAlong(nodes, hops)...)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return comparePaths(paths[i], paths[j]) < 0
+	})
+	if options.MaxPaths > 0 && len(paths) > options.MaxPaths {
+		paths = paths[:options.MaxPaths]
+	}
+	return paths, nil
+}
+
+// pathEnds resolves the entities at both ends of a path.
+// The caller must hold at least a read lock.
+func (kg *KG) pathEnds(from, to string, caseSensitiveSearch bool) (*Node, *Node, error) {
+	var ends [2]*Node
+	for i, reference := range []string{from, to} {
+		node, err := kg.resolveEntity(reference, caseSensitiveSearch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if node == nil {
+			return nil, nil, fmt.Errorf("entity %q not found", reference)
+		}
+		ends[i] = node
+	}
+	if ends[0] == ends[1] {
+		return nil, nil, fmt.Errorf("%q and %q are the same entity", from, to)
+	}
+	return ends[0], ends[1], nil
+}
+
+// traversableHops returns the hops leaving each entity along the triples selected by the options,
+// sorted by the entity they reach, then by label. Triples with a literal object and self-loops are left out.
+// The caller must hold at least a read lock.
+func (kg *KG) traversableHops(options PathOptions) map[int64][]Hop {
+	hops := make(map[int64][]Hop)
+	for _, pred := range kg.allPredicates() {
+		subject, object := pred.F.(*Node), pred.T.(*Node)
+		if object.IsLiteral() || subject == object || pred.Subject == "" || !inGraphs(pred.Graph, options.Graphs) {
+			continue
+		}
+		hops[subject.ID()] = append(hops[subject.ID()], Hop{From: subject, To: object, Triple: pred})
+		if options.Undirected {
+			hops[object.ID()] = append(hops[object.ID()], Hop{From: object, To: subject, Triple: pred, Reversed: true})
+		}
+	}
+	for _, leaving := range hops {
+		sort.Slice(leaving, func(i, j int) bool {
+			return compareHops(leaving[i], leaving[j]) < 0
+		})
+	}
+	return hops
+}
+
+// hopGraph returns a directed graph of the entities with an edge from an entity to another
+// wherever a hop leaves the first one for the second.
+func hopGraph(hops map[int64][]Hop) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for _, leaving := range hops {
+		for _, hop := range leaving {
+			if g.Node(hop.From.ID()) == nil {
+				g.AddNode(simple.Node(hop.From.ID()))
+			}
+			if g.Node(hop.To.ID()) == nil {
+				g.AddNode(simple.Node(hop.To.ID()))
+			}
+			g.SetEdge(simple.Edge{F: simple.Node(hop.From.ID()), T: simple.Node(hop.To.ID())})
+		}
+	}
+	return g
+}
+
+// reversedGraph is a directed graph whose edges are followed from their end to their start.
+type reversedGraph struct {
+	graph.Directed
+}
+
+// From returns the nodes with an edge to the node with the given ID.
+func (g reversedGraph) From(id int64) graph.Nodes {
+	return g.Directed.To(id)
+}
+
+// Edge returns the edge from the node with ID vid to the node with ID uid, if it exists.
+func (g reversedGraph) Edge(uid, vid int64) graph.Edge {
+	return g.Directed.Edge(vid, uid)
+}
+
+// distancesTo returns the number of hops from each entity of the graph of the hops to the entity to,
+// for the entities from which to can be reached.
+func distancesTo(to *Node, g *simple.DirectedGraph) map[int64]int {
+	distances := make(map[int64]int)
+	if g.Node(to.ID()) == nil {
+		return distances
+	}
+	var walk traverse.BreadthFirst
+	walk.Walk(reversedGraph{g}, simple.Node(to.ID()), func(node graph.Node, depth int) bool {
+		distances[node.ID()] = depth
+		return false
+	})
+	return distances
+}
+
+// pathsAlong returns the paths following the sequence of entities, one for each choice among the hops
+// linking each entity to the next one.
+func pathsAlong(nodes []graph.Node, hops map[int64][]Hop) []Path {
+	paths := []Path{nil}
+	for i := 1; i < len(nodes); i++ {
+		var linking []Hop
+		for _, hop := range hops[nodes[i-1].ID()] {
+			if hop.To.ID() == nodes[i].ID() {
+				linking = append(linking, hop)
+			}
+		}
+		next := make([]Path, 0, len(paths)*len(linking))
+		for _, p := range paths {
+			for _, hop := range linking {
+				next = append(next, append(slices.Clone(p), hop))
+			}
+		}
+		paths = next
+	}
+	return paths
+}
+
+// compareHops compares two hops leaving the same entity by the entity they reach, their label and their direction.
+func compareHops(a, b Hop) int {
+	if c := compareNodes(a.To, b.To); c != 0 {
+		return c
+	}
+	if c := compareLabels(a.Label(), b.Label()); c != 0 {
+		return c
+	}
+	if a.Reversed != b.Reversed {
+		if b.Reversed {
+			return -1
+		}
+		return 1
+	}
+	return compareOrdered(a.Triple.Identifier, b.Triple.Identifier)
+}
+
+// comparePaths compares two paths from the same entity by number of hops, then hop by hop.
+func comparePaths(a, b Path) int {
+	if len(a) != len(b) {
+		return compareOrdered(int64(len(a)), int64(len(b)))
+	}
+	for i := range a {
+		if c := compareHops(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package kg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaths(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	for _, triple := range [][3]string{
+		{"Alice", "works_for", "Acme"},
+		{"Bob", "works_for", "Acme"},
+		{"Alice", "knows", "Carol"},
+		{"Carol", "knows", "Bob"},
+		{"Carol", "friend_of", "Bob"},
+		{"Bob", "lives_in", "Paris"},
+		{"Dave", "lives_in", "Lyon"},
+	} {
+		assert.NoError(kg.InsertTriple(triple[0], triple[1], triple[2], true))
+	}
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "born", Object: "1990", ObjectType: DatatypeInteger}, true))
+	assert.NoError(kg.InsertTriple("Alice", "reports_to", "Bob", true, "hr"))
+
+	// Shortest path
+	path, err := kg.ShortestPath("Alice", "Paris", PathOptions{}, true)
+	assert.NoError(err)
+	assert.Equal([]string{"Alice", "reports_to", "Bob", "lives_in", "Paris"}, path.Labels())
+	assert.Equal("Alice -[reports_to]-> Bob -[lives_in]-> Paris", path.String())
+
+	path, err = kg.ShortestPath("alice", "paris", PathOptions{Graphs: []string{DefaultGraph}}, false)
+	assert.NoError(err)
+	assert.Equal([]string{"Alice", "knows", "Carol", "friend_of", "Bob", "lives_in", "Paris"}, path.Labels(), "Ties are broken by label")
+
+	path, err = kg.ShortestPath("Bob", "Alice", PathOptions{}, true)
+	assert.NoError(err)
+	assert.Nil(path, "Triples are traversed from subject to object")
+	path, err = kg.ShortestPath("Bob", "Alice", PathOptions{Undirected: true, Graphs: []string{DefaultGraph}}, true)
+	assert.NoError(err)
+	assert.Equal("Bob -[works_for]-> Acme <-[works_for]- Alice", path.String())
+	assert.Equal([]string{"Bob", "works_for", "Acme", "^works_for", "Alice"}, path.Labels())
+
+	path, err = kg.ShortestPath("Alice", "Lyon", PathOptions{Undirected: true}, true)
+	assert.NoError(err)
+	assert.Nil(path)
+	_, err = kg.ShortestPath("Alice", "Nobody", PathOptions{}, true)
+	assert.Error(err)
+	_, err = kg.ShortestPath("Alice", "alice", PathOptions{}, false)
+	assert.Error(err)
+
+	// All paths
+	paths, err := kg.AllPaths("Alice", "Bob", 2, PathOptions{}, true)
+	assert.NoError(err)
+	var descriptions []string
+	for _, path := range paths {
+		descriptions = append(descriptions, path.String())
+	}
+	assert.Equal([]string{
+		"Alice -[reports_to]-> Bob",
+		"Alice -[knows]-> Carol -[friend_of]-> Bob",
+		"Alice -[knows]-> Carol -[knows]-> Bob",
+	}, descriptions)
+
+	paths, err = kg.AllPaths("Alice", "Bob", 3, PathOptions{Undirected: true, MaxPaths: 2}, true)
+	assert.NoError(err)
+	assert.Len(paths, 2)
+	assert.Len(paths[1], 2, "The shortest paths are kept")
+
+	paths, err = kg.AllPaths("Alice", "Bob", 3, PathOptions{Undirected: true}, true)
+	assert.NoError(err)
+	assert.Len(paths, 4)
+	for _, path := range paths {
+		visited := map[*Node]bool{}
+		for _, hop := range path {
+			assert.False(visited[hop.From], "Paths do not go through an entity twice")
+			visited[hop.From] = true
+		}
+	}
+
+	_, err = kg.AllPaths("Alice", "Bob", 0, PathOptions{}, true)
+	assert.Error(err)
+}
@@ -1,16 +1,37 @@
 package kg
 
 import (
+	"strings"
+
 	"gonum.org/v1/gonum/graph"
 )
 
-// Predicate is an edge of the graph. It fulfills the graph.Edge interface.
+// Predicate is an edge of the graph. It fulfills the graph.Edge and graph.Line interfaces.
 // It represents a relationship between two nodes with a subject describing the relationship.
+// Several predicates may link the same pair of nodes; each of them is identified by a
+// unique Identifier.
+// Each predicate belongs to a named graph, the default graph being named DefaultGraph,
+// and carries the provenance of the triple it represents.
+// A triple inferred by a rule rather than asserted is marked with the label of the rule.
 type Predicate struct {
-	F, T    graph.Node
-	Subject string
+	F, T       graph.Node
+	Subject    string
+	Identifier int64
+	Graph      string   // Name of the graph holding the triple
+	Metadata   Metadata // Provenance of the triple
+	Rule       string   // Label of the rule that inferred the triple, empty for asserted triples
+
+	inverse string // Declared inverse of the predicate, kept up to date by the graph holding it
+}
+
+// Inferred reports whether the triple was inferred by a rule rather than asserted.
+func (predicate *Predicate) Inferred() bool {
+	return predicate.Rule != ""
 }
 
+// DefaultGraph is the name of the graph holding the triples inserted without a graph name.
+const DefaultGraph = ""
+
 // From returns the from node of the edge.
 func (predicate *Predicate) From() graph.Node {
 	return predicate.F
@@ -21,17 +42,47 @@ func (predicate *Predicate) To() graph.Node {
 	return predicate.T
 }
 
-// ReversedEdge returns the edge reversal of the receiver
-// if a reversal is valid for the data type.
-// When a reversal is valid an edge of the same type as
-// the receiver with nodes of the receiver swapped should
-// be returned, otherwise the receiver should be returned
-// unaltered.
-// ReversedEdge returns a new Predicate with the From and To nodes swapped.
+// ID returns the unique identifier of the predicate.
+// This method satisfies the graph.Line interface.
+func (predicate *Predicate) ID() int64 {
+	return predicate.Identifier
+}
+
+// ReversedEdge returns a new Predicate with the From and To nodes swapped, labeled so that it states the same fact:
+// its subject is the declared inverse of the predicate, the predicate itself if it is symmetric,
+// or the predicate prefixed with ^ otherwise, as in ^parent_of.
 // This method satisfies the graph.Edge interface.
 func (predicate *Predicate) ReversedEdge() graph.Edge {
 	return &Predicate{
-		F: predicate.T,
-		T: predicate.F,
+		F:        predicate.T,
+		T:        predicate.F,
+		Subject:  predicate.inverseSubject(),
+		Graph:    predicate.Graph,
+		Metadata: predicate.Metadata,
+		Rule:     predicate.Rule,
+		inverse:  predicate.Subject,
+	}
+}
+
+// inverseSubject returns the subject of the reverse of the predicate.
+func (predicate *Predicate) inverseSubject() string {
+	switch {
+	case predicate.inverse != "":
+		return predicate.inverse
+	case predicate.Subject == "":
+		return ""
+	case strings.HasPrefix(predicate.Subject, "^"):
+		return predicate.Subject[1:]
+	default:
+		return "^" + predicate.Subject
 	}
 }
+
+// ReversedLine returns a new Predicate with the From and To nodes swapped
+// and the same Identifier as the receiver.
+// This method satisfies the graph.Line interface.
+func (predicate *Predicate) ReversedLine() graph.Line {
+	reversed := predicate.ReversedEdge().(*Predicate)
+	reversed.Identifier = predicate.Identifier
+	return reversed
+}
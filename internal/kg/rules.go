@@ -0,0 +1,439 @@
+package kg
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Atom is a triple pattern of a rule. A term starting with a question mark, such as ?x, is a variable;
+// any other subject or object is a constant matching the labels of an entity or the value of a literal.
+// The predicate is always a constant, compared exactly.
+type Atom struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+}
+
+// String returns the atom in the (subject, predicate, object) notation.
+func (atom Atom) String() string {
+	return "(" + atom.Subject + ", " + atom.Predicate + ", " + atom.Object + ")"
+}
+
+// isVariable reports whether the term of an atom is a variable.
+func isVariable(term string) bool {
+	return strings.HasPrefix(term, "?") && len(term) > 1
+}
+
+// Rule is a Datalog-style rule: whenever every atom of the body matches triples of the graph
+// with consistent variable bindings, the head, with the same bindings, is an inferred triple.
+type Rule struct {
+	Name string `json:"name,omitempty"`
+	Body []Atom `json:"body"`
+	Head Atom   `json:"head"`
+}
+
+// TransitiveRule returns the rule stating that predicate is transitive:
+// (?x, predicate, ?y), (?y, predicate, ?z) -> (?x, predicate, ?z).
+func TransitiveRule(predicate string) Rule {
+	return Rule{
+		Name: predicate + " is transitive",
+		Body: []Atom{{"?x", predicate, "?y"}, {"?y", predicate, "?z"}},
+		Head: Atom{"?x", predicate, "?z"},
+	}
+}
+
+// ChainRule returns the rule stating that following first then second implies result:
+// (?x, first, ?y), (?y, second, ?z) -> (?x, result, ?z).
+func ChainRule(first, second, result string) Rule {
+	return Rule{
+		Name: first + " then " + second + " implies " + result,
+		Body: []Atom{{"?x", first, "?y"}, {"?y", second, "?z"}},
+		Head: Atom{"?x", result, "?z"},
+	}
+}
+
+// InverseRule returns the rule stating that inverse is the inverse of predicate:
+// (?x, predicate, ?y) -> (?y, inverse, ?x).
+func InverseRule(predicate, inverse string) Rule {
+	return Rule{
+		Name: predicate + " has inverse " + inverse,
+		Body: []Atom{{"?x", predicate, "?y"}},
+		Head: Atom{"?y", inverse, "?x"},
+	}
+}
+
+var (
+	atomPattern       = regexp.MustCompile(`\(([^()]*)\)`)
+	transitivePattern = regexp.MustCompile(`^(.+?)\s+is\s+transitive$`)
+	inversePattern    = regexp.MustCompile(`^(.+?)\s+has\s+inverse\s+(.+)$`)
+)
+
+// ParseRule parses a rule written as its body and head separated by an arrow, such as
+// "(?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z)", or as one of the shorthands
+// "is_part_of is transitive" and "cites has inverse cited_by".
+// A rule may be named by prefixing it with its name and a colon, as in "containment: is_part_of is transitive".
+func ParseRule(text string) (Rule, error) {
+	text = strings.TrimSpace(text)
+
+	// An optional name precedes the rule
+	var name string
+	if i := strings.Index(text, ":"); i >= 0 && !strings.ContainsAny(text[:i], "(?") {
+		name, text = strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+	}
+
+	var rule Rule
+	if matches := transitivePattern.FindStringSubmatch(text); matches != nil {
+		rule = TransitiveRule(strings.TrimSpace(matches[1]))
+	} else if matches := inversePattern.FindStringSubmatch(text); matches != nil {
+		rule = InverseRule(strings.TrimSpace(matches[1]), strings.TrimSpace(matches[2]))
+	} else {
+		body, head, found := strings.Cut(text, "->")
+		if !found {
+			return Rule{}, fmt.Errorf("invalid rule %q: expecting body -> head, \"p is transitive\" or \"p has inverse q\"", text)
+		}
+		atoms, err := parseAtoms(body)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid rule %q: %w", text, err)
+		}
+		heads, err := parseAtoms(head)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid rule %q: %w", text, err)
+		}
+		if len(heads) != 1 {
+			return Rule{}, fmt.Errorf("invalid rule %q: the head must be a single atom", text)
+		}
+		rule = Rule{Body: atoms, Head: heads[0]}
+	}
+
+	if name != "" {
+		rule.Name = name
+	}
+	return rule, rule.Validate()
+}
+
+// parseAtoms parses a comma-separated list of atoms in the (subject, predicate, object) notation.
+func parseAtoms(text string) ([]Atom, error) {
+	var atoms []Atom
+	for _, match := range atomPattern.FindAllStringSubmatch(text, -1) {
+		terms := strings.Split(match[1], ",")
+		if len(terms) != 3 {
+			return nil, fmt.Errorf("atom %q must have a subject, a predicate and an object", match[0])
+		}
+		atoms = append(atoms, Atom{
+			Subject:   strings.TrimSpace(terms[0]),
+			Predicate: strings.TrimSpace(terms[1]),
+			Object:    strings.TrimSpace(terms[2]),
+		})
+	}
+	if len(atoms) == 0 {
+		return nil, fmt.Errorf("no atom in %q", strings.TrimSpace(text))
+	}
+	return atoms, nil
+}
+
+// Validate checks that the rule has a body, that its predicates are constants
+// and that every variable of its head appears in its body.
+func (rule Rule) Validate() error {
+	if len(rule.Body) == 0 {
+		return fmt.Errorf("invalid rule %s: empty body", rule)
+	}
+
+	bound := make(map[string]bool)
+	for _, atom := range append(append([]Atom(nil), rule.Body...), rule.Head) {
+		if atom.Subject == "" || atom.Predicate == "" || atom.Object == "" {
+			return fmt.Errorf("invalid rule %s: atom %s has an empty term", rule, atom)
+		}
+		if isVariable(atom.Predicate) {
+			return fmt.Errorf("invalid rule %s: the predicate of atom %s cannot be a variable", rule, atom)
+		}
+	}
+	for _, atom := range rule.Body {
+		bound[atom.Subject] = isVariable(atom.Subject)
+		bound[atom.Object] = isVariable(atom.Object)
+	}
+	for _, term := range []string{rule.Head.Subject, rule.Head.Object} {
+		if !isVariable(term) {
+			return fmt.Errorf("invalid rule %s: the head can only hold variables, not %q", rule, term)
+		}
+		if !bound[term] {
+			return fmt.Errorf("invalid rule %s: variable %s of the head does not appear in the body", rule, term)
+		}
+	}
+	return nil
+}
+
+// String returns the rule in the body -> head notation, preceded by its name if it has one.
+func (rule Rule) String() string {
+	atoms := make([]string, len(rule.Body))
+	for i, atom := range rule.Body {
+		atoms[i] = atom.String()
+	}
+	text := strings.Join(atoms, ", ") + " -> " + rule.Head.String()
+	if rule.Name != "" {
+		text = rule.Name + ": " + text
+	}
+	return text
+}
+
+// label returns the name of the rule, or its notation if it has no name.
+func (rule Rule) label() string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return rule.String()
+}
+
+// SetRules validates the rules and makes them the rule set of the graph, replacing the previous one.
+// Triples materialized by the previous rules are kept until Materialize or ClearInferred is called.
+func (kg *KG) SetRules(rules []Rule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.rules = append([]Rule(nil), rules...)
+	kg.invalidateInferred()
+	return nil
+}
+
+// renamePredicateRules replaces the predicate oldName with newName in the atoms of the rules.
+// The rules are copied rather than changed in place, since the previous rule set may be kept in the history.
+// The caseSensitiveSearch parameter determines if the predicate matching is case-sensitive.
+// The caller must hold the write lock.
+func (kg *KG) renamePredicateRules(oldName, newName string, caseSensitiveSearch bool) {
+	rules := make([]Rule, len(kg.rules))
+	renamed := false
+	for i, rule := range kg.rules {
+		rule.Body = slices.Clone(rule.Body)
+		for j := range rule.Body {
+			if predicateMatches(rule.Body[j].Predicate, oldName, caseSensitiveSearch) {
+				rule.Body[j].Predicate = newName
+				renamed = true
+			}
+		}
+		if predicateMatches(rule.Head.Predicate, oldName, caseSensitiveSearch) {
+			rule.Head.Predicate = newName
+			renamed = true
+		}
+		rules[i] = rule
+	}
+	if renamed {
+		kg.rules = rules
+		kg.invalidateInferred()
+	}
+}
+
+// Rules returns a copy of the rule set of the graph.
+func (kg *KG) Rules() []Rule {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return append([]Rule(nil), kg.rules...)
+}
+
+// Materialize stores the triples inferred by the rule set of the graph, until no rule infers anything new.
+// The triples materialized earlier are removed first, so that the result reflects the current triples and rules.
+// Materialized triples are marked with the rule that inferred them; see Predicate.Rule.
+// It returns the number of inferred triples stored.
+func (kg *KG) Materialize() int {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.clearInferred()
+	inferred := kg.infer()
+	for _, pred := range inferred {
+		// Literals are never shared between triples
+		if objectNode := pred.T.(*Node); objectNode.IsLiteral() {
+			pred.T = kg.newLiteralNode(Literal{Value: objectNode.Lexical, Datatype: objectNode.Datatype, Language: objectNode.Language})
+		}
+		pred.Identifier = kg.currentLineID
+		kg.currentLineID++
+		kg.addPredicate(pred)
+	}
+	return len(inferred)
+}
+
+// ClearInferred removes the materialized triples from the graph, along with their literal values.
+// It returns the number of triples removed.
+func (kg *KG) ClearInferred() int {
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	return kg.clearInferred()
+}
+
+// clearInferred removes the materialized triples from the graph.
+// The caller must hold the write lock.
+func (kg *KG) clearInferred() int {
+	removed := 0
+	for _, pred := range kg.allPredicates() {
+		if pred.Rule == "" {
+			continue
+		}
+		kg.removePredicate(pred)
+		if objectNode := pred.T.(*Node); objectNode.IsLiteral() {
+			kg.removeNode(objectNode)
+		}
+		removed++
+	}
+	return removed
+}
+
+// binding maps the variables of a rule to nodes of the graph.
+type binding map[string]*Node
+
+// infer evaluates the rule set of the graph against its triples, including the materialized ones,
+// until no rule infers anything new. It returns the inferred triples that are not in the graph,
+// as predicates that are not added to it. An inferred triple belongs to the graph of the triples
+// it is inferred from when they all belong to the same graph, and to the default graph otherwise.
+// The caller must hold at least a read lock.
+func (kg *KG) infer() []*Predicate {
+	if len(kg.rules) == 0 {
+		return nil
+	}
+
+	// Index the known triples by predicate, and by statement to detect the new ones
+	facts := make(map[string][]*Predicate)
+	known := make(map[string]bool)
+	for _, pred := range kg.allPredicates() {
+		facts[pred.Subject] = append(facts[pred.Subject], pred)
+		known[statementKey(pred)] = true
+	}
+
+	now := time.Now()
+	var inferred []*Predicate
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range kg.rules {
+			var conclusions []*Predicate
+			kg.matchBody(rule.Body, facts, binding{}, nil, func(b binding, premises []*Predicate) {
+				subjectNode, objectNode := b[rule.Head.Subject], b[rule.Head.Object]
+				if subjectNode.IsLiteral() {
+					return
+				}
+				graph := premises[0].Graph
+				for _, premise := range premises[1:] {
+					if premise.Graph != graph {
+						graph = DefaultGraph
+						break
+					}
+				}
+				conclusions = append(conclusions, &Predicate{
+					F:        subjectNode,
+					T:        objectNode,
+					Subject:  rule.Head.Predicate,
+					Graph:    graph,
+					Metadata: Metadata{Created: now, Modified: now},
+					Rule:     rule.label(),
+				})
+			})
+
+			for _, pred := range conclusions {
+				key := statementKey(pred)
+				if known[key] {
+					continue
+				}
+				known[key] = true
+				facts[pred.Subject] = append(facts[pred.Subject], pred)
+				inferred = append(inferred, pred)
+				changed = true
+			}
+		}
+	}
+	return inferred
+}
+
+// inferredPredicates returns the triples inferred by the rules, as infer does.
+// The rules are only evaluated again once the triples or the rules have changed;
+// the predicates returned are shared between the calls and must not be modified.
+// The caller must hold at least a read lock.
+func (kg *KG) inferredPredicates() []*Predicate {
+	kg.inferring.Lock()
+	defer kg.inferring.Unlock()
+
+	if !kg.inferredValid {
+		kg.inferred = kg.infer()
+		kg.inferredValid = true
+	}
+	return kg.inferred
+}
+
+// invalidateInferred forgets the triples inferred by the rules, so that they are evaluated again.
+// It must be called when the rules change, and is called by the indexes on every change of a triple
+// or of the labels of a node.
+// The caller must hold the write lock.
+func (kg *KG) invalidateInferred() {
+	kg.inferred = nil
+	kg.inferredValid = false
+}
+
+// matchBody calls yield with every binding of the variables for which the atoms match known triples,
+// along with the triples matched.
+func (kg *KG) matchBody(atoms []Atom, facts map[string][]*Predicate, b binding, premises []*Predicate, yield func(binding, []*Predicate)) {
+	if len(atoms) == 0 {
+		yield(b, premises)
+		return
+	}
+
+	atom := atoms[0]
+	for _, pred := range facts[atom.Predicate] {
+		extended, ok := unify(b, atom.Subject, pred.F.(*Node))
+		if !ok {
+			continue
+		}
+		if extended, ok = unify(extended, atom.Object, pred.T.(*Node)); !ok {
+			continue
+		}
+		kg.matchBody(atoms[1:], facts, extended, append(premises, pred), yield)
+	}
+}
+
+// unify returns the binding extended so that term designates node, and false if it cannot be.
+// The binding passed as a parameter is not modified.
+func unify(b binding, term string, node *Node) (binding, bool) {
+	if !isVariable(term) {
+		return b, node.matches(term, true)
+	}
+	if bound, ok := b[term]; ok {
+		return b, sameValue(bound, node)
+	}
+
+	extended := make(binding, len(b)+1)
+	for variable, value := range b {
+		extended[variable] = value
+	}
+	extended[term] = node
+	return extended, true
+}
+
+// sameValue reports whether two nodes designate the same entity or the same literal value.
+// Literal nodes are never shared, so literals are compared by value.
+func sameValue(a, b *Node) bool {
+	if a == b {
+		return true
+	}
+	return a.IsLiteral() && b.IsLiteral() &&
+		a.Lexical == b.Lexical && a.Datatype == b.Datatype && a.Language == b.Language
+}
+
+// statementKey returns a key identifying the triple stated by the predicate in its graph,
+// literal objects being identified by value.
+func statementKey(pred *Predicate) string {
+	return fmt.Sprintf("%d\x00%s\x00%s\x00%s", pred.F.ID(), pred.Subject, valueKey(pred.T.(*Node)), pred.Graph)
+}
+
+// valueKey returns a key identifying the value of an object node:
+// the node itself for an entity, and its value for a literal since literal nodes are never shared.
+func valueKey(node *Node) string {
+	if node.IsLiteral() {
+		return node.String()
+	}
+	return fmt.Sprintf("#%d", node.ID())
+}
@@ -0,0 +1,182 @@
+package kg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRule(t *testing.T) {
+	assert := assert.New(t)
+
+	rule, err := ParseRule("(?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z)")
+	assert.NoError(err)
+	assert.Equal(Rule{
+		Body: []Atom{{"?x", "located_in", "?y"}, {"?y", "located_in", "?z"}},
+		Head: Atom{"?x", "located_in", "?z"},
+	}, rule)
+	assert.Equal("(?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z)", rule.String())
+
+	rule, err = ParseRule("is_part_of is transitive")
+	assert.NoError(err)
+	assert.Equal(TransitiveRule("is_part_of"), rule)
+
+	rule, err = ParseRule("citations: cites has inverse cited_by")
+	assert.NoError(err)
+	assert.Equal("citations", rule.Name)
+	assert.Equal([]Atom{{"?x", "cites", "?y"}}, rule.Body)
+	assert.Equal(Atom{"?y", "cited_by", "?x"}, rule.Head)
+
+	_, err = ParseRule("located_in implies near")
+	assert.Error(err)
+	_, err = ParseRule("(?x, located_in, ?y) -> (?x, near, ?z)")
+	assert.Error(err, "?z is not bound by the body")
+	_, err = ParseRule("(?x, ?p, ?y) -> (?y, ?p, ?x)")
+	assert.Error(err, "Predicates cannot be variables")
+	_, err = ParseRule("(?x, located_in, ?y) -> (?x, near, Paris)")
+	assert.Error(err, "The head only holds variables")
+}
+
+func TestRules(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Montmartre", "located_in", "Paris", true))
+	assert.NoError(kg.InsertTriple("Paris", "located_in", "France", true))
+	assert.NoError(kg.InsertTriple("France", "located_in", "Europe", true, "geography"))
+	assert.NoError(kg.InsertTriple("Alice", "cites", "Bob", true))
+	assert.NoError(kg.Insert(Triple{Subject: "France", Predicate: "population", Object: "68000000", ObjectType: DatatypeInteger}, true))
+
+	chain, err := ParseRule("(?x, located_in, ?y), (?y, population, ?p) -> (?x, country_population, ?p)")
+	assert.NoError(err)
+	assert.NoError(kg.SetRules([]Rule{TransitiveRule("located_in"), InverseRule("cites", "cited_by"), chain}))
+
+	inferred := func() [][3]string {
+		predicates, err := kg.MatchTriples(TriplePattern{IncludeInferred: true}, true)
+		assert.NoError(err)
+		var result [][3]string
+		for _, pred := range predicates {
+			if pred.Inferred() {
+				result = appendTriple(result, pred)
+			}
+		}
+		return result
+	}
+	expected := [][3]string{
+		{"Montmartre", "located_in", "France"},
+		{"Paris", "located_in", "Europe"},
+		{"Montmartre", "located_in", "Europe"},
+		{"Bob", "cited_by", "Alice"},
+		{"Paris", "country_population", "68000000"},
+		{"Montmartre", "country_population", "68000000"},
+	}
+
+	// Rules are evaluated at query time
+	assert.ElementsMatch(expected, inferred())
+	assert.Len(kg.FindTriples("", "", "", true), 5, "Only asserted triples are found by default")
+
+	// An inferred triple from premises in different graphs belongs to the default graph
+	predicates, err := kg.MatchTriples(TriplePattern{Subject: "Paris", Predicate: "located_in", Graphs: []string{DefaultGraph}, IncludeInferred: true}, true)
+	assert.NoError(err)
+	assert.Len(predicates, 2)
+
+	// Materialized triples are stored and marked with their rule
+	assert.Equal(6, kg.Materialize())
+	assert.ElementsMatch(expected, inferred())
+	assert.Len(kg.FindTriples("", "", "", true), 5)
+	predicates, err = kg.MatchTriples(TriplePattern{Subject: "Bob", IncludeInferred: true}, true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal("cites has inverse cited_by", predicates[0].Rule)
+
+	// Rules and materialized triples are stored with the graph
+	var buf bytes.Buffer
+	assert.NoError(WriteTo(&buf, kg))
+	restored, err := ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Len(restored.Rules(), 3)
+	predicates, err = restored.MatchTriples(TriplePattern{Subject: "Bob", IncludeInferred: true}, true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.True(predicates[0].Inferred())
+
+	// Asserting an inferred triple makes it an asserted triple
+	assert.NoError(kg.InsertTriple("Bob", "cited_by", "Alice", true))
+	assert.Len(kg.FindTriples("Bob", "cited_by", "Alice", true), 1)
+	assert.Equal(5, kg.ClearInferred())
+	assert.Len(kg.FindTriples("Bob", "cited_by", "Alice", true), 1)
+
+	// Materializing again reflects the removal of a premise
+	removed, err := kg.RemoveTriple("Paris", "located_in", "France", true)
+	assert.NoError(err)
+	assert.True(removed)
+	assert.Equal(0, kg.Materialize(), "Nothing is inferred without Paris located_in France")
+	assert.Empty(inferred())
+}
+
+func TestInferredCache(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Montmartre", "located_in", "Paris", true))
+	assert.NoError(kg.InsertTriple("Paris", "located_in", "France", true))
+	assert.NoError(kg.SetRules([]Rule{TransitiveRule("located_in")}))
+
+	inferred := func() []*Predicate {
+		predicates, err := kg.MatchTriples(TriplePattern{Predicate: "located_in", IncludeInferred: true}, true)
+		assert.NoError(err)
+		var result []*Predicate
+		for _, pred := range predicates {
+			if pred.Inferred() {
+				result = append(result, pred)
+			}
+		}
+		return result
+	}
+
+	// The rules are evaluated once until the graph changes
+	first := inferred()
+	assert.Len(first, 1)
+	assert.Same(first[0], inferred()[0])
+
+	assert.NoError(kg.InsertTriple("France", "located_in", "Europe", true))
+	assert.Len(inferred(), 3, "A new triple is taken into account")
+	_, err := kg.RenameEntity("Europe", "European Union", true)
+	assert.NoError(err)
+	predicates, err := kg.MatchTriples(TriplePattern{Object: "European Union", IncludeInferred: true}, true)
+	assert.NoError(err)
+	assert.Len(predicates, 3, "A renamed entity is taken into account")
+	assert.NoError(kg.SetRules(nil))
+	assert.Empty(inferred(), "A change of the rules is taken into account")
+}
+
+func TestRenamePredicateRules(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.InsertTriple("Alice", "parent_of", "Bob", true))
+	assert.NoError(kg.InsertTriple("Bob", "parnt_of", "Carol", true))
+	assert.NoError(kg.SetRules([]Rule{
+		{Name: "grandparent", Body: []Atom{{"?x", "Parnt_of", "?y"}, {"?y", "parnt_of", "?z"}}, Head: Atom{"?x", "grandparent_of", "?z"}},
+		TransitiveRule("located_in"),
+	}))
+	rules := kg.Rules()
+
+	// The rules follow the renamed predicate
+	_, err := kg.RenamePredicate("parnt_of", "parent_of", false)
+	assert.NoError(err)
+	assert.Equal([]Atom{{"?x", "parent_of", "?y"}, {"?y", "parent_of", "?z"}}, kg.Rules()[0].Body)
+	assert.Equal(TransitiveRule("located_in"), kg.Rules()[1])
+	assert.Equal("Parnt_of", rules[0].Body[0].Predicate, "The previous rules are left unchanged")
+	predicates, err := kg.MatchTriples(TriplePattern{Predicate: "grandparent_of", IncludeInferred: true}, true)
+	assert.NoError(err)
+	assert.Len(predicates, 1)
+	assert.Equal([][3]string{{"Alice", "grandparent_of", "Carol"}}, appendTriple(nil, predicates[0]))
+
+	// The head of a rule is renamed as well
+	assert.Equal(1, kg.Materialize())
+	_, err = kg.RenamePredicate("grandparent_of", "ancestor_of", true)
+	assert.NoError(err)
+	assert.Equal(Atom{"?x", "ancestor_of", "?z"}, kg.Rules()[0].Head)
+}
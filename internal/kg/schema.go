@@ -0,0 +1,306 @@
+package kg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TypePredicate is the predicate stating that an entity is an instance of a class,
+// as in (Python, rdf:type, Programming Language).
+const TypePredicate = "rdf:type"
+
+// SubClassOfPredicate is the predicate stating that every instance of a class is also an instance of another class,
+// as in (Interpreted Language, rdfs:subClassOf, Programming Language).
+const SubClassOfPredicate = "rdfs:subClassOf"
+
+// Enforcement tells what happens when a triple violates the schema.
+type Enforcement string
+
+// The enforcement modes of a schema.
+const (
+	EnforcementWarn   Enforcement = "warn"   // Triples violating the schema are inserted; CheckTriple reports the violations
+	EnforcementStrict Enforcement = "strict" // Triples violating the schema are rejected
+)
+
+// Schema is the ontology of a knowledge graph: the classes of its entities and
+// the predicates that may link them.
+type Schema struct {
+	Enforcement Enforcement           `json:"enforcement,omitempty"`
+	Classes     []ClassDefinition     `json:"classes,omitempty"`
+	Predicates  []PredicateDefinition `json:"predicates,omitempty"`
+}
+
+// ClassDefinition declares a class of entities.
+type ClassDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PredicateDefinition declares a predicate.
+// The domain is the class of the subjects of the predicate.
+// The range is either the class of its objects or the datatype of its literal values.
+// An empty domain or range is not checked.
+type PredicateDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	Range       string `json:"range,omitempty"`
+}
+
+// Validate checks that the enforcement mode is known, that classes and predicates are declared once
+// and that the domains and ranges are declared classes or datatypes.
+func (schema *Schema) Validate() error {
+	switch schema.Enforcement {
+	case "", EnforcementWarn, EnforcementStrict:
+	default:
+		return fmt.Errorf("invalid schema: unknown enforcement %q", schema.Enforcement)
+	}
+
+	classes := make(map[string]bool, len(schema.Classes))
+	for _, class := range schema.Classes {
+		if class.Name == "" {
+			return fmt.Errorf("invalid schema: class without a name")
+		}
+		if classes[foldKey(class.Name)] {
+			return fmt.Errorf("invalid schema: class %q is declared twice", class.Name)
+		}
+		classes[foldKey(class.Name)] = true
+	}
+
+	predicates := make(map[string]bool, len(schema.Predicates))
+	for _, predicate := range schema.Predicates {
+		if predicate.Name == "" {
+			return fmt.Errorf("invalid schema: predicate without a name")
+		}
+		if predicates[foldKey(predicate.Name)] {
+			return fmt.Errorf("invalid schema: predicate %q is declared twice", predicate.Name)
+		}
+		predicates[foldKey(predicate.Name)] = true
+
+		if predicate.Domain != "" && !classes[foldKey(predicate.Domain)] {
+			return fmt.Errorf("invalid schema: domain %q of predicate %q is not a declared class", predicate.Domain, predicate.Name)
+		}
+		if predicate.Range != "" && !classes[foldKey(predicate.Range)] && rangeDatatype(predicate.Range) == "" {
+			return fmt.Errorf("invalid schema: range %q of predicate %q is neither a declared class nor a datatype", predicate.Range, predicate.Name)
+		}
+	}
+
+	return nil
+}
+
+// rangeDatatype returns the datatype named by the range of a predicate, or an empty datatype
+// if the range is not a datatype.
+func rangeDatatype(name string) Datatype {
+	if strings.EqualFold(name, "entity") {
+		return ""
+	}
+	datatype, err := ParseDatatype(name)
+	if err != nil {
+		return ""
+	}
+	return datatype
+}
+
+// Class returns the declaration of the class, or nil.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+func (schema *Schema) Class(name string, caseSensitiveSearch bool) *ClassDefinition {
+	for i := range schema.Classes {
+		if predicateMatches(schema.Classes[i].Name, name, caseSensitiveSearch) {
+			return &schema.Classes[i]
+		}
+	}
+	return nil
+}
+
+// Predicate returns the declaration of the predicate, or nil.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+func (schema *Schema) Predicate(name string, caseSensitiveSearch bool) *PredicateDefinition {
+	for i := range schema.Predicates {
+		if predicateMatches(schema.Predicates[i].Name, name, caseSensitiveSearch) {
+			return &schema.Predicates[i]
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy of the schema.
+func (schema *Schema) clone() *Schema {
+	if schema == nil {
+		return nil
+	}
+	return &Schema{
+		Enforcement: schema.Enforcement,
+		Classes:     append([]ClassDefinition(nil), schema.Classes...),
+		Predicates:  append([]PredicateDefinition(nil), schema.Predicates...),
+	}
+}
+
+// renamePredicate returns a copy of the schema where the definition of the predicate oldName is named newName,
+// merged with the definition of newName if there is one. The schema itself is returned if it does not define oldName.
+// It returns an error if both predicates are defined with different domains or ranges.
+func (schema *Schema) renamePredicate(oldName, newName string) (*Schema, error) {
+	if schema == nil || schema.Predicate(oldName, false) == nil {
+		return schema, nil
+	}
+	result := schema.clone()
+	definition := *result.Predicate(oldName, false)
+	definition.Name = newName
+	existing := result.Predicate(newName, false)
+	if existing == nil || foldKey(oldName) == foldKey(newName) {
+		*result.Predicate(oldName, false) = definition
+		return result, nil
+	}
+
+	// Complete the definition of newName with the one of oldName
+	if existing.Domain != "" && definition.Domain != "" && foldKey(existing.Domain) != foldKey(definition.Domain) {
+		return nil, fmt.Errorf("the schema defines %q and %q with different domains", oldName, newName)
+	}
+	if existing.Range != "" && definition.Range != "" && foldKey(existing.Range) != foldKey(definition.Range) {
+		return nil, fmt.Errorf("the schema defines %q and %q with different ranges", oldName, newName)
+	}
+	if existing.Domain == "" {
+		existing.Domain = definition.Domain
+	}
+	if existing.Range == "" {
+		existing.Range = definition.Range
+	}
+	if existing.Description == "" {
+		existing.Description = definition.Description
+	}
+	result.Predicates = slices.DeleteFunc(result.Predicates, func(predicate PredicateDefinition) bool {
+		return foldKey(predicate.Name) == foldKey(oldName)
+	})
+	return result, nil
+}
+
+// SchemaError lists the violations of the schema by a triple.
+type SchemaError struct {
+	Violations []string
+}
+
+// Error returns the violations separated by semicolons.
+func (err *SchemaError) Error() string {
+	return "schema violation: " + strings.Join(err.Violations, "; ")
+}
+
+// SetSchema validates the schema and makes it the active schema of the graph.
+// Existing triples are not checked. A nil schema removes the active schema.
+func (kg *KG) SetSchema(schema *Schema) error {
+	if schema != nil {
+		if err := schema.Validate(); err != nil {
+			return err
+		}
+	}
+
+	kg.mu.Lock()
+	defer kg.mu.Unlock()
+
+	kg.schema = schema.clone()
+	return nil
+}
+
+// Schema returns a copy of the active schema of the graph, or nil if there is none.
+func (kg *KG) Schema() *Schema {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return kg.schema.clone()
+}
+
+// CheckTriple checks the triple against the active schema of the graph.
+// It returns a *SchemaError listing the violations, or nil if the triple conforms to the schema.
+// Entities without any type are not checked against the domain and range of predicates.
+// The caseSensitiveSearch parameter determines if entity, predicate and class matching is case-sensitive.
+func (kg *KG) CheckTriple(triple Triple, caseSensitiveSearch bool) error {
+	kg.readLock()
+	defer kg.mu.RUnlock()
+
+	return kg.checkTriple(triple, caseSensitiveSearch)
+}
+
+// checkTriple checks the triple against the active schema of the graph.
+// The caller must hold at least a read lock.
+func (kg *KG) checkTriple(triple Triple, caseSensitiveSearch bool) error {
+	schema := kg.schema
+	if schema == nil {
+		return nil
+	}
+
+	var violations []string
+	isLiteral := triple.ObjectType != "" || triple.Language != ""
+
+	if predicateMatches(triple.Predicate, TypePredicate, caseSensitiveSearch) {
+		if isLiteral {
+			violations = append(violations, fmt.Sprintf("the object of %s must be a class, not a literal value", TypePredicate))
+		} else if len(schema.Classes) > 0 && schema.Class(triple.Object, caseSensitiveSearch) == nil {
+			violations = append(violations, fmt.Sprintf("class %q is not declared", triple.Object))
+		}
+	} else if predicateMatches(triple.Predicate, SubClassOfPredicate, caseSensitiveSearch) {
+		if isLiteral {
+			violations = append(violations, fmt.Sprintf("the object of %s must be a class, not a literal value", SubClassOfPredicate))
+		} else if len(schema.Classes) > 0 {
+			for _, class := range []string{triple.Subject, triple.Object} {
+				if schema.Class(class, caseSensitiveSearch) == nil {
+					violations = append(violations, fmt.Sprintf("class %q is not declared", class))
+				}
+			}
+		}
+	} else if definition := schema.Predicate(triple.Predicate, caseSensitiveSearch); definition == nil {
+		if len(schema.Predicates) > 0 {
+			violations = append(violations, fmt.Sprintf("predicate %q is not declared", triple.Predicate))
+		}
+	} else {
+		if definition.Domain != "" {
+			if types := kg.typesOf(triple.Subject, triple.SubjectKey, caseSensitiveSearch); len(types) > 0 && !hasClass(types, definition.Domain, caseSensitiveSearch) {
+				violations = append(violations, fmt.Sprintf("subject %q of %s is a %s, not a %s", triple.Subject, definition.Name, strings.Join(types, ", "), definition.Domain))
+			}
+		}
+		if definition.Range != "" {
+			if datatype := rangeDatatype(definition.Range); datatype != "" {
+				objectType := triple.ObjectType
+				if objectType == "" && triple.Language != "" {
+					objectType = DatatypeLangString
+				}
+				if objectType != datatype {
+					violations = append(violations, fmt.Sprintf("object %q of %s must be a %s literal", triple.Object, definition.Name, datatype))
+				}
+			} else if isLiteral {
+				violations = append(violations, fmt.Sprintf("object %q of %s must be a %s entity, not a literal value", triple.Object, definition.Name, definition.Range))
+			} else if types := kg.typesOf(triple.Object, triple.ObjectKey, caseSensitiveSearch); len(types) > 0 && !hasClass(types, definition.Range, caseSensitiveSearch) {
+				violations = append(violations, fmt.Sprintf("object %q of %s is a %s, not a %s", triple.Object, definition.Name, strings.Join(types, ", "), definition.Range))
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &SchemaError{Violations: violations}
+}
+
+// typesOf returns the labels of the classes of the entity designated by the key or, if empty, the label,
+// including their superclasses.
+// It returns nil if the entity does not exist, is ambiguous or has no type.
+// The caller must hold at least a read lock.
+func (kg *KG) typesOf(label, key string, caseSensitiveSearch bool) []string {
+	node, err := kg.lookupEntity(label, key, caseSensitiveSearch)
+	if err != nil || node == nil {
+		return nil
+	}
+
+	classes := kg.directTypes(node, nil)
+	classes = append(classes, kg.reachable(classes, SubClassOfPredicate, true, nil)...)
+	return sortedLabels(classes)
+}
+
+// hasClass reports whether class is one of the types.
+// The caseSensitiveSearch parameter determines if the comparison is case-sensitive.
+func hasClass(types []string, class string, caseSensitiveSearch bool) bool {
+	for _, t := range types {
+		if predicateMatches(t, class, caseSensitiveSearch) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package kg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSchema returns the ontology of the extract-relations-from-text prompt.
+func newTestSchema(enforcement Enforcement) *Schema {
+	return &Schema{
+		Enforcement: enforcement,
+		Classes: []ClassDefinition{
+			{Name: "Person"},
+			{Name: "Manager"},
+			{Name: "Team"},
+		},
+		Predicates: []PredicateDefinition{
+			{Name: "worksFor", Domain: "Person", Range: "Manager"},
+			{Name: "isMemberOf", Domain: "Person", Range: "Team"},
+			{Name: "hasLeader", Domain: "Team", Range: "Manager"},
+			{Name: "hiredOn", Domain: "Person", Range: "date"},
+		},
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(newTestSchema(EnforcementStrict).Validate())
+	assert.Error((&Schema{Enforcement: "sometimes"}).Validate())
+	assert.Error((&Schema{Classes: []ClassDefinition{{Name: "Person"}, {Name: "person"}}}).Validate())
+	assert.Error((&Schema{Predicates: []PredicateDefinition{{Name: "worksFor", Domain: "Person"}}}).Validate(), "The domain is not declared")
+	assert.Error((&Schema{Predicates: []PredicateDefinition{{Name: "age", Range: "Number"}}}).Validate(), "The range is neither a class nor a datatype")
+	assert.NoError((&Schema{Predicates: []PredicateDefinition{{Name: "age", Range: "integer"}}}).Validate())
+}
+
+func TestInsertWithSchema(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	assert.NoError(kg.SetSchema(newTestSchema(EnforcementStrict)))
+	assert.NoError(kg.InsertTriple("Alice", TypePredicate, "Person", true))
+	assert.NoError(kg.InsertTriple("Bob", TypePredicate, "Manager", true))
+	assert.NoError(kg.InsertTriple("Core", TypePredicate, "Team", true))
+
+	assert.NoError(kg.InsertTriple("Alice", "worksFor", "Bob", true))
+	assert.NoError(kg.InsertTriple("Carol", "worksFor", "Bob", true), "Untyped entities are not checked")
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "hiredOn", Object: "2020-01-06", ObjectType: DatatypeDate}, true))
+
+	var schemaErr *SchemaError
+	assert.ErrorAs(kg.InsertTriple("Alice", "hasLeader", "Bob", true), &schemaErr, "Alice is not a Team")
+	assert.ErrorAs(kg.InsertTriple("Alice", "isMemberOf", "Bob", true), &schemaErr, "Bob is not a Team")
+	assert.ErrorAs(kg.InsertTriple("Alice", "likes", "Bob", true), &schemaErr, "likes is not declared")
+	assert.ErrorAs(kg.InsertTriple("Alice", TypePredicate, "Robot", true), &schemaErr, "Robot is not a class")
+	assert.ErrorAs(kg.InsertTriple("Alice", "hiredOn", "2020-01-06", true), &schemaErr, "The hiring date must be a date literal")
+	assert.Empty(kg.FindTriples("", "hasLeader", "", true))
+	assert.Empty(kg.FindTriples("", "likes", "", true))
+
+	// A schema enforced with warnings reports the violations without rejecting the triple
+	schema := kg.Schema()
+	schema.Enforcement = EnforcementWarn
+	assert.NoError(kg.SetSchema(schema))
+	triple := Triple{Subject: "Alice", Predicate: "hasLeader", Object: "Bob"}
+	err := kg.CheckTriple(triple, true)
+	assert.ErrorAs(err, &schemaErr)
+	assert.Equal([]string{`subject "Alice" of hasLeader is a Person, not a Team`}, schemaErr.Violations)
+	assert.NoError(kg.Insert(triple, true))
+
+	// The schema is stored with the graph
+	var buf bytes.Buffer
+	assert.NoError(WriteTo(&buf, kg))
+	restored, err := ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(schema, restored.Schema())
+
+	assert.NoError(kg.SetSchema(nil))
+	assert.Nil(kg.Schema())
+	assert.NoError(kg.CheckTriple(triple, true))
+}
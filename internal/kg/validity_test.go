@@ -0,0 +1,74 @@
+package kg
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidity(t *testing.T) {
+	kg := NewKG("sample")
+	assert := assert.New(t)
+
+	date := func(value string) time.Time {
+		result, err := time.Parse(time.DateOnly, value)
+		assert.NoError(err)
+		return result
+	}
+
+	assert.NoError(kg.DeclareFunctional("works_for", ConflictReject))
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "works_for", Object: "Acme", ValidFrom: date("2018-01-01"), ValidTo: date("2021-01-01")}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "works_for", Object: "Globex", ValidFrom: date("2021-01-01")}, true), "Successive values do not conflict")
+	assert.Error(kg.Insert(Triple{Subject: "Alice", Predicate: "works_for", Object: "Initech", ValidFrom: date("2022-01-01")}, true), "Concurrent values conflict")
+	assert.Error(kg.Insert(Triple{Subject: "Alice", Predicate: "lives_in", Object: "Paris", ValidFrom: date("2022-01-01"), ValidTo: date("2021-01-01")}, true))
+
+	// As-of queries
+	assert.Len(kg.FindTriples("Alice", "works_for", "", true), 2, "A zero time selects the whole history")
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}}, kg.FindTriplesAsOf("Alice", "works_for", "", date("2019-06-01"), true))
+	assert.Equal([][3]string{{"Alice", "works_for", "Globex"}}, kg.FindTriplesAsOf("Alice", "works_for", "", date("2021-01-01"), true), "The end of a period is excluded")
+	assert.Empty(kg.FindTriplesAsOf("Alice", "works_for", "", date("2017-01-01"), true))
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}}, kg.DescribeEntityAsOf("Acme", date("2019-06-01"), true))
+	assert.Empty(kg.DescribeEntityAsOf("Acme", date("2022-06-01"), true))
+
+	// Closing a fact keeps it in the history
+	closed, err := kg.CloseTriple("Alice", "works_for", "", date("2023-03-01"), true)
+	assert.NoError(err)
+	assert.Equal(1, closed, "Only the facts still holding are closed")
+	assert.Empty(kg.FindTriplesAsOf("Alice", "works_for", "", date("2023-06-01"), true))
+	assert.Len(kg.FindTriples("Alice", "works_for", "", true), 2)
+	assert.NoError(kg.Insert(Triple{Subject: "Alice", Predicate: "works_for", Object: "Initech", ValidFrom: date("2023-03-01")}, true))
+
+	_, err = kg.CloseTriple("Bob", "works_for", "", time.Time{}, true)
+	assert.Error(err)
+	_, err = kg.CloseTriple("Alice", "works_for", "Initech", date("2020-01-01"), true)
+	assert.Error(err, "A period cannot end before it starts")
+
+	// A fact holding again after being closed starts a new period
+	assert.NoError(kg.InsertTriple("Bob", "member_of", "Club", true))
+	closed, err = kg.CloseTriple("bob", "MEMBER_OF", "club", time.Time{}, false)
+	assert.NoError(err)
+	assert.Equal(1, closed)
+	assert.NoError(kg.Insert(Triple{Subject: "Bob", Predicate: "member_of", Object: "Club", ValidFrom: time.Now().Add(time.Hour)}, true))
+	assert.Len(kg.FindTriples("Bob", "member_of", "Club", true), 2)
+	assert.Len(kg.FindTriplesAsOf("Bob", "member_of", "Club", time.Now(), true), 0)
+
+	// Overlapping values stored before the declaration are contradictions, successive ones are not
+	assert.NoError(kg.Insert(Triple{Subject: "Carol", Predicate: "lives_in", Object: "Paris", ValidTo: date("2020-01-01")}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Carol", Predicate: "lives_in", Object: "Lyon", ValidFrom: date("2020-01-01")}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Dave", Predicate: "lives_in", Object: "Rome", ValidFrom: date("2019-01-01")}, true))
+	assert.NoError(kg.Insert(Triple{Subject: "Dave", Predicate: "lives_in", Object: "Milan", ValidFrom: date("2020-01-01")}, true))
+	assert.NoError(kg.DeclareFunctional("lives_in", ConflictFlag))
+	contradictions := kg.FindContradictions("", true)
+	assert.Len(contradictions, 1)
+	assert.Equal("Dave", contradictions[0].Subject.Lexical)
+	assert.Equal([]string{"Rome", "Milan"}, contradictions[0].Values)
+
+	// The validity is saved with the graph
+	var buffer bytes.Buffer
+	assert.NoError(WriteTo(&buffer, kg))
+	loaded, err := ReadFrom(&buffer)
+	assert.NoError(err)
+	assert.Equal([][3]string{{"Alice", "works_for", "Acme"}}, loaded.FindTriplesAsOf("Alice", "works_for", "", date("2019-06-01"), true))
+}
@@ -2,6 +2,9 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/owulveryck/mcpkg/internal/kg"
@@ -27,6 +30,39 @@ func InsertTriple() mcp.Tool {
 			mcp.Required(),
 			mcp.Description("the object of the triple"),
 		),
+		mcp.WithString("object_type",
+			mcp.Description("the type of the object: an entity (default) or a literal value of type string, integer, decimal, date (YYYY-MM-DD), dateTime (RFC 3339) or boolean"),
+			mcp.Enum("entity", "string", "integer", "decimal", "date", "dateTime", "boolean"),
+		),
+		mcp.WithString("object_language",
+			mcp.Description("the language tag of a string literal object (for example en or fr)"),
+		),
+		mcp.WithString("graph",
+			mcp.Description("the name of the graph holding the triple (leave empty or use default for the default graph)"),
+		),
+		mcp.WithString("subject_key",
+			mcp.Description("a stable identifier of the subject, such as an IRI, to tell apart entities sharing a label; the subject is created with this key if it does not exist"),
+		),
+		mcp.WithString("object_key",
+			mcp.Description("a stable identifier of an entity object, such as an IRI; the object is created with this key if it does not exist"),
+		),
+		mcp.WithString("source",
+			mcp.Description("where the fact comes from, such as a document URI or a conversation id"),
+		),
+		mcp.WithString("agent",
+			mcp.Description("the name of the agent asserting the fact"),
+		),
+		mcp.WithNumber("confidence",
+			mcp.Description("the confidence in the fact, between 0 and 1"),
+			mcp.Min(0),
+			mcp.Max(1),
+		),
+		mcp.WithString("valid_from",
+			mcp.Description("the time from which the fact holds, as a date (YYYY-MM-DD) or a date-time (RFC 3339); always if empty"),
+		),
+		mcp.WithString("valid_to",
+			mcp.Description("the time from which the fact no longer holds, as a date (YYYY-MM-DD) or a date-time (RFC 3339); still holding if empty"),
+		),
 	)
 }
 
@@ -36,9 +72,98 @@ func InsertTripleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	predicate := request.Params.Arguments["predicate"].(string)
 	object := request.Params.Arguments["object"].(string)
 
+	// Extract optional parameters describing a literal object
+	var objectType, objectLanguage string
+	if val, ok := request.Params.Arguments["object_type"]; ok && val != nil {
+		objectType = val.(string)
+	}
+	if val, ok := request.Params.Arguments["object_language"]; ok && val != nil {
+		objectLanguage = val.(string)
+	}
+
+	// Extract the optional graph name
+	var graph string
+	if val, ok := request.Params.Arguments["graph"]; ok && val != nil {
+		graph = graphName(val.(string))
+	}
+
+	// Extract the optional keys of the entities
+	var subjectKey, objectKey string
+	if val, ok := request.Params.Arguments["subject_key"]; ok && val != nil {
+		subjectKey = val.(string)
+	}
+	if val, ok := request.Params.Arguments["object_key"]; ok && val != nil {
+		objectKey = val.(string)
+	}
+
+	// Extract the optional provenance of the fact
+	var source, agent string
+	var confidence float64
+	if val, ok := request.Params.Arguments["source"]; ok && val != nil {
+		source = val.(string)
+	}
+	if val, ok := request.Params.Arguments["agent"]; ok && val != nil {
+		agent = val.(string)
+	}
+	if val, ok := request.Params.Arguments["confidence"]; ok && val != nil {
+		confidence = val.(float64)
+	}
+
+	// Extract the optional validity period of the fact
+	var validFrom, validTo string
+	if val, ok := request.Params.Arguments["valid_from"]; ok && val != nil {
+		validFrom = val.(string)
+	}
+	if val, ok := request.Params.Arguments["valid_to"]; ok && val != nil {
+		validTo = val.(string)
+	}
+
 	// Use the file-safe modifier function
+	var warnings []string
 	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
-		return g.InsertTriple(subject, predicate, object, false)
+		datatype, err := kg.ParseDatatype(objectType)
+		if err != nil {
+			return err
+		}
+		start, err := timestamp(validFrom)
+		if err != nil {
+			return err
+		}
+		end, err := timestamp(validTo)
+		if err != nil {
+			return err
+		}
+		triple := kg.Triple{
+			Subject:    subject,
+			Predicate:  predicate,
+			Object:     object,
+			ObjectType: datatype,
+			Language:   objectLanguage,
+			Graph:      graph,
+			SubjectKey: subjectKey,
+			ObjectKey:  objectKey,
+			Source:     source,
+			Agent:      agent,
+			Confidence: confidence,
+			ValidFrom:  start,
+			ValidTo:    end,
+		}
+
+		// A schema that is not strictly enforced only produces a warning
+		if err := g.CheckTriple(triple, false); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		if err := g.Insert(triple, false); err != nil {
+			return err
+		}
+
+		// So does a flagged conflict with the declared cardinality of the predicate
+		for _, contradiction := range g.FindContradictions(subject, false) {
+			if strings.EqualFold(contradiction.Predicate, predicate) {
+				warnings = append(warnings, contradiction.String())
+			}
+		}
+		return nil
 	})
 	
 	if err != nil {
@@ -53,11 +178,16 @@ func InsertTripleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		}, nil
 	}
 	
+	text := "success"
+	for _, warning := range warnings {
+		text += "\nwarning: " + warning
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: "success",
+				Text: text,
 			},
 		},
 		IsError: false,
@@ -84,6 +214,12 @@ func RemoveTriple() mcp.Tool {
 			mcp.Required(),
 			mcp.Description("the object of the triple to remove"),
 		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs to remove the triple from, default naming the default graph (leave empty to remove it from every graph)"),
+		),
+		mcp.WithBoolean("remove_orphans",
+			mcp.Description("whether to also delete the subject and object when they are left without any relationship"),
+		),
 	)
 }
 
@@ -93,14 +229,42 @@ func RemoveTripleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	predicate := request.Params.Arguments["predicate"].(string)
 	object := request.Params.Arguments["object"].(string)
 
-	// First read the graph to check if it's empty
-	g, err := ReadKnowledgeGraph(graphPath)
+	// Extract the optional graph names
+	var graphs []string
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		graphs = graphNames(val.(string))
+	}
+	removeOrphans := false
+	if val, ok := request.Params.Arguments["remove_orphans"]; ok && val != nil {
+		removeOrphans = val.(bool)
+	}
+
+	// Use the file-safe modifier function, recording the removal in the history of the graph
+	// An ambiguous subject or object must be designated by its key
+	empty, removed := false, false
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		// If the graph has no nodes, it's effectively empty
+		if len(g.ListNodes()) == 0 {
+			empty = true
+			return nil
+		}
+		g.PruneOrphans = removeOrphans
+		var err error
+		removed, err = g.RemoveTriple(subject, predicate, object, false, graphs...)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
 	}
-	
-	// If the graph has no nodes, it's effectively empty
-	if len(g.ListNodes()) == 0 {
+	if empty {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.TextContent{
@@ -111,9 +275,7 @@ func RemoveTripleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp
 			IsError: false,
 		}, nil
 	}
-	
-	// Check if the triple exists
-	if !g.RemoveTriple(subject, predicate, object, false) {
+	if !removed {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.TextContent{
@@ -124,18 +286,385 @@ func RemoveTripleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp
 			IsError: false,
 		}, nil
 	}
-	
-	// If the triple exists and was removed, save the updated graph
-	err = WriteKnowledgeGraph(graphPath, g)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "Triple successfully removed.",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func RetractSource() mcp.Tool {
+	return mcp.NewTool(
+		"retract_source",
+		mcp.WithDescription("Remove every triple coming from a given source, and the entities left without any relationship"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("source",
+			mcp.Required(),
+			mcp.Description("the exact source recorded when the triples were inserted"),
+		),
+	)
+}
+
+func RetractSourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	source := request.Params.Arguments["source"].(string)
+
+	// Use the file-safe modifier function
+	var triples, nodes int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		triples, nodes = g.RemoveBySource(source)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	
+
+	if triples == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "No triples found for source: " + source,
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: "Triple successfully removed.",
+				Text: fmt.Sprintf("Removed %d triples and %d nodes from source: %s", triples, nodes, source),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func MergeEntities() mcp.Tool {
+	return mcp.NewTool(
+		"merge_entities",
+		mcp.WithDescription("Merge a duplicate entity into another one: its relationships are moved to the kept entity and its name becomes an alias of it"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("keep",
+			mcp.Required(),
+			mcp.Description("the entity to keep, such as Guido van Rossum"),
+		),
+		mcp.WithString("drop",
+			mcp.Required(),
+			mcp.Description("the duplicate entity to merge into the kept one, such as G. van Rossum"),
+		),
+	)
+}
+
+func MergeEntitiesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	keep := request.Params.Arguments["keep"].(string)
+	drop := request.Params.Arguments["drop"].(string)
+
+	// Use the file-safe modifier function
+	var moved, duplicates int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		var err error
+		moved, duplicates, err = g.MergeEntities(keep, drop, false)
+		return err
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Merged %s into %s: %d triples moved, %d duplicate triples removed.", drop, keep, moved, duplicates),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func AddAlias() mcp.Tool {
+	return mcp.NewTool(
+		"add_alias",
+		mcp.WithDescription("Add an alternate label to an entity, so that the alias can be used wherever the entity is expected"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("entity",
+			mcp.Required(),
+			mcp.Description("the entity to add the alias to"),
+		),
+		mcp.WithString("alias",
+			mcp.Required(),
+			mcp.Description("the alternate label of the entity"),
+		),
+	)
+}
+
+func AddAliasHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	entity := request.Params.Arguments["entity"].(string)
+	alias := request.Params.Arguments["alias"].(string)
+
+	// Use the file-safe modifier function
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		return g.AddAlias(entity, alias, false)
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "success",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func RemoveAlias() mcp.Tool {
+	return mcp.NewTool(
+		"remove_alias",
+		mcp.WithDescription("Remove an alternate label from an entity"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("entity",
+			mcp.Required(),
+			mcp.Description("the entity to remove the alias from"),
+		),
+		mcp.WithString("alias",
+			mcp.Required(),
+			mcp.Description("the alternate label to remove"),
+		),
+	)
+}
+
+func RemoveAliasHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	entity := request.Params.Arguments["entity"].(string)
+	alias := request.Params.Arguments["alias"].(string)
+
+	// Use the file-safe modifier function
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		return g.RemoveAlias(entity, alias, false)
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "success",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func RenameEntity() mcp.Tool {
+	return mcp.NewTool(
+		"rename_entity",
+		mcp.WithDescription("Rename an entity in every triple; if the new name is already used by another entity, both entities are merged"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("old_name",
+			mcp.Required(),
+			mcp.Description("the current name of the entity"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("the new name of the entity"),
+		),
+	)
+}
+
+func RenameEntityHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	oldName := request.Params.Arguments["old_name"].(string)
+	newName := request.Params.Arguments["new_name"].(string)
+
+	// Use the file-safe modifier function
+	var changed int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		var err error
+		changed, err = g.RenameEntity(oldName, newName, false)
+		return err
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Renamed entity %s to %s: %d triples changed.", oldName, newName, changed),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func RenamePredicate() mcp.Tool {
+	return mcp.NewTool(
+		"rename_predicate",
+		mcp.WithDescription("Rename a predicate in every triple; triples that already exist with the new name are merged, and its declarations, schema definition and rules move to the new name"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("old_name",
+			mcp.Required(),
+			mcp.Description("the current name of the predicate"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("the new name of the predicate"),
+		),
+	)
+}
+
+func RenamePredicateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	oldName := request.Params.Arguments["old_name"].(string)
+	newName := request.Params.Arguments["new_name"].(string)
+
+	// Use the file-safe modifier function
+	var changed int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		var err error
+		changed, err = g.RenamePredicate(oldName, newName, false)
+		return err
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Renamed predicate %s to %s: %d triples changed.", oldName, newName, changed),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func DeleteEntity() mcp.Tool {
+	return mcp.NewTool(
+		"delete_entity",
+		mcp.WithDescription("Delete an entity and every triple in which it appears"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("entity",
+			mcp.Required(),
+			mcp.Description("the entity to delete"),
+		),
+		mcp.WithBoolean("remove_orphans",
+			mcp.Description("whether to also delete the entities left without any relationship"),
+		),
+	)
+}
+
+func DeleteEntityHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	entity := request.Params.Arguments["entity"].(string)
+	removeOrphans := false
+	if val, ok := request.Params.Arguments["remove_orphans"]; ok && val != nil {
+		removeOrphans = val.(bool)
+	}
+
+	// Use the file-safe modifier function
+	var removed int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		g.PruneOrphans = removeOrphans
+		var err error
+		removed, err = g.RemoveEntity(entity, false)
+		return err
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Deleted entity %s and %d triples.", entity, removed),
 			},
 		},
 		IsError: false,
@@ -159,6 +688,36 @@ func FindTriples() mcp.Tool {
 		mcp.WithString("object",
 			mcp.Description("the object to search for (leave empty to match any object)"),
 		),
+		mcp.WithString("filter",
+			mcp.Description("an optional condition on the object, such as \"> 1990\", \"<= 2000-01-01\", \"between 1990 and 2000\", \"!= false\" or \"prefix Py\"; numbers, dates and strings are compared according to their type and a quoted value is a string"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs to search, default naming the default graph (leave empty to search every graph)"),
+		),
+		mcp.WithBoolean("include_metadata",
+			mcp.Description("whether to return the provenance of each triple: creation and modification times, source, agent and confidence"),
+		),
+		mcp.WithBoolean("include_inferred",
+			mcp.Description("whether to also return the triples inferred by the rules of the knowledge graph, each marked with the rule that inferred it"),
+		),
+		mcp.WithString("as_of",
+			mcp.Description("only return the facts holding at that time, as a date (YYYY-MM-DD) or a date-time (RFC 3339); leave empty to return the whole history"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("the order of the triples: by subject (default), predicate, object or creation time; labels are compared case-insensitively and numbers and dates by value"),
+			mcp.Enum(string(kg.SortBySubject), string(kg.SortByPredicate), string(kg.SortByObject), string(kg.SortByCreation)),
+		),
+		mcp.WithBoolean("descending",
+			mcp.Description("whether to return the triples in the reverse order"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("the maximum number of triples to return (leave empty to return them all)"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("the number of triples to skip, to get the next page of results"),
+			mcp.Min(0),
+		),
 	)
 }
 
@@ -176,6 +735,52 @@ func FindTriplesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 	if val, ok := request.Params.Arguments["object"]; ok && val != nil {
 		object = val.(string)
 	}
+	var graphs []string
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		graphs = graphNames(val.(string))
+	}
+	includeMetadata := false
+	if val, ok := request.Params.Arguments["include_metadata"]; ok && val != nil {
+		includeMetadata = val.(bool)
+	}
+	includeInferred := false
+	if val, ok := request.Params.Arguments["include_inferred"]; ok && val != nil {
+		includeInferred = val.(bool)
+	}
+	var asOf time.Time
+	if val, ok := request.Params.Arguments["as_of"]; ok && val != nil {
+		var err error
+		asOf, err = timestamp(val.(string))
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: err.Error(),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+	}
+
+	// Parse the optional filter on the object
+	var filter *kg.Filter
+	if val, ok := request.Params.Arguments["filter"]; ok && val != nil && val.(string) != "" {
+		var err error
+		filter, err = kg.ParseFilter(val.(string))
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: err.Error(),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+	}
 
 	// Read the graph using the thread-safe method
 	g, err := ReadKnowledgeGraph(graphPath)
@@ -196,10 +801,30 @@ func FindTriplesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		}, nil
 	}
 
-	// Find triples matching the criteria
-	triples := g.FindTriples(subject, predicate, object, false)
+	// Find the requested page of the triples matching the criteria
+	options := queryOptions(request)
+	triples, total, err := g.QueryTriples(kg.TriplePattern{
+		Subject:         subject,
+		Predicate:       predicate,
+		Object:          object,
+		Filter:          filter,
+		Graphs:          graphs,
+		IncludeInferred: includeInferred,
+		AsOf:            asOf,
+	}, options, false)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
 	
-	if len(triples) == 0 {
+	if total == 0 {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.TextContent{
@@ -212,9 +837,22 @@ func FindTriplesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 	}
 
 	// Format the results
-	result := "Found triples:\n"
+	result := "Found triples (" + pageDescription(total, options.Offset, len(triples)) + "):\n"
 	for i, triple := range triples {
-		result += "- (" + triple[0] + ", " + triple[1] + ", " + triple[2] + ")\n"
+		result += "- (" + triple.F.(*kg.Node).String() + ", " + triple.Subject + ", " + triple.T.(*kg.Node).String() + ")"
+		if triple.Graph != kg.DefaultGraph {
+			result += " in graph " + triple.Graph
+		}
+		if triple.Inferred() {
+			result += " (inferred by " + triple.Rule + ")"
+		}
+		if validity := triple.Metadata.Validity(); validity != "" {
+			result += " (" + validity + ")"
+		}
+		if metadata := triple.Metadata.String(); includeMetadata && metadata != "" {
+			result += " [" + metadata + "]"
+		}
+		result += "\n"
 		// Add a newline after 10 triples for better readability, but not after the last one
 		if i > 0 && i%10 == 0 && i < len(triples)-1 {
 			result += "\n"
@@ -244,6 +882,27 @@ func DescribeEntity() mcp.Tool {
 			mcp.Required(),
 			mcp.Description("the entity to describe"),
 		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs to search, default naming the default graph (leave empty to search every graph)"),
+		),
+		mcp.WithString("as_of",
+			mcp.Description("only return the facts holding at that time, as a date (YYYY-MM-DD) or a date-time (RFC 3339); leave empty to return the whole history"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("the order of the triples: by subject (default), predicate, object or creation time; labels are compared case-insensitively and numbers and dates by value"),
+			mcp.Enum(string(kg.SortBySubject), string(kg.SortByPredicate), string(kg.SortByObject), string(kg.SortByCreation)),
+		),
+		mcp.WithBoolean("descending",
+			mcp.Description("whether to return the triples in the reverse order"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("the maximum number of triples to return (leave empty to return them all)"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("the number of triples to skip, to get the next page of results"),
+			mcp.Min(0),
+		),
 	)
 }
 
@@ -251,6 +910,30 @@ func DescribeEntityHandler(ctx context.Context, request mcp.CallToolRequest) (*m
 	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
 	entity := request.Params.Arguments["entity"].(string)
 
+	// Extract the optional graph names
+	var graphs []string
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		graphs = graphNames(val.(string))
+	}
+
+	// Extract the optional time at which the facts must hold
+	var asOf time.Time
+	if val, ok := request.Params.Arguments["as_of"]; ok && val != nil {
+		var err error
+		asOf, err = timestamp(val.(string))
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: err.Error(),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+	}
+
 	// Read the graph using the thread-safe method
 	g, err := ReadKnowledgeGraph(graphPath)
 	if err != nil {
@@ -270,10 +953,40 @@ func DescribeEntityHandler(ctx context.Context, request mcp.CallToolRequest) (*m
 		}, nil
 	}
 
-	// Get all triples involving the entity
-	triples := g.DescribeEntity(entity, false)
+	// Resolve the entity, which must not be ambiguous
+	node, err := g.ResolveEntity(entity, false)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	// Get all triples involving the entity, designated by its key when it has one
+	reference := entity
+	if node != nil && node.Key != "" {
+		reference = node.Key
+	}
+	options := queryOptions(request)
+	triples, total, err := g.DescribeEntityPage(reference, asOf, options, false, graphs...)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
 	
-	if len(triples) == 0 {
+	if total == 0 {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.TextContent{
@@ -285,8 +998,23 @@ func DescribeEntityHandler(ctx context.Context, request mcp.CallToolRequest) (*m
 		}, nil
 	}
 
-	// Format the results
-	result := "Entity: " + entity + "\n\n"
+	// Format the results, resolving a key or an alias to the canonical label of the entity
+	entity = node.Lexical
+	result := "Entity: " + entity + "\n"
+	if node.Key != "" {
+		result += "Key: " + node.Key + "\n"
+	}
+	if len(node.Aliases) > 0 {
+		result += "Also known as: " + strings.Join(node.Aliases, ", ") + "\n"
+	}
+	if types, err := g.Types(reference, false, false, graphs...); err == nil && len(types) > 0 {
+		result += "Types: " + strings.Join(types, ", ") + "\n"
+		if inherited, _ := g.Types(reference, true, false, graphs...); len(inherited) > len(types) {
+			result += "Inherited types: " + strings.Join(withoutLabels(inherited, types), ", ") + "\n"
+		}
+	}
+	result += "Triples: " + pageDescription(total, options.Offset, len(triples)) + "\n"
+	result += "\n"
 	
 	// Group by "as subject" and "as object" for better organization
 	result += "As subject:\n"
@@ -325,3 +1053,157 @@ func DescribeEntityHandler(ctx context.Context, request mcp.CallToolRequest) (*m
 		IsError: false,
 	}, nil
 }
+
+func ListInstances() mcp.Tool {
+	return mcp.NewTool(
+		"list_instances",
+		mcp.WithDescription("List the entities that are instances of a class (linked to it by rdf:type), including the instances of its subclasses (linked by rdfs:subClassOf), transitively"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("class",
+			mcp.Required(),
+			mcp.Description("the class whose instances to list, such as Programming Language"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs to search, default naming the default graph (leave empty to search every graph)"),
+		),
+	)
+}
+
+func ListInstancesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	class := request.Params.Arguments["class"].(string)
+
+	// Extract the optional graph names
+	var graphs []string
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		graphs = graphNames(val.(string))
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := g.ListInstances(class, false, graphs...)
+	if len(instances) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "No instances found for class: " + class,
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	// List each instance with the classes it is directly asserted to belong to
+	result := fmt.Sprintf("Instances of %s (%d):\n", class, len(instances))
+	for _, instance := range instances {
+		result += "- " + instance.Lexical
+		if types, err := g.Types(instance.Key, false, false, graphs...); err == nil && len(types) > 0 {
+			result += " (" + strings.Join(types, ", ") + ")"
+		}
+		result += "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// withoutLabels returns the labels that are not in excluded.
+func withoutLabels(labels, excluded []string) []string {
+	var result []string
+	for _, label := range labels {
+		found := false
+		for _, other := range excluded {
+			if label == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// queryOptions returns the order and the page of results requested by the optional sort_by, descending,
+// limit and offset arguments of a tool. Invalid values are reported by the query.
+func queryOptions(request mcp.CallToolRequest) kg.QueryOptions {
+	var options kg.QueryOptions
+	if val, ok := request.Params.Arguments["sort_by"]; ok && val != nil {
+		options.SortBy = kg.SortOrder(val.(string))
+	}
+	if val, ok := request.Params.Arguments["descending"]; ok && val != nil {
+		options.Descending = val.(bool)
+	}
+	if val, ok := request.Params.Arguments["limit"]; ok && val != nil {
+		options.Limit = int(val.(float64))
+	}
+	if val, ok := request.Params.Arguments["offset"]; ok && val != nil {
+		options.Offset = int(val.(float64))
+	}
+	return options
+}
+
+// pageDescription describes a page of count results starting at offset among total results,
+// such as "25 in total, showing 11 to 20".
+func pageDescription(total, offset, count int) string {
+	switch {
+	case count == total:
+		return fmt.Sprintf("%d in total", total)
+	case count == 0:
+		return fmt.Sprintf("%d in total, none after %d", total, offset)
+	default:
+		return fmt.Sprintf("%d in total, showing %d to %d", total, offset+1, offset+count)
+	}
+}
+
+// graphName returns the name of the graph designated by a tool argument.
+// The name default designates the default graph.
+func graphName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "default" {
+		return kg.DefaultGraph
+	}
+	return name
+}
+
+// graphNames returns the names of the graphs listed in a comma-separated tool argument.
+// An empty list selects every graph.
+func graphNames(list string) []string {
+	if strings.TrimSpace(list) == "" {
+		return nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		names = append(names, graphName(name))
+	}
+	return names
+}
+
+// predicateNames returns the predicates listed in a comma-separated tool argument.
+// An empty list selects every predicate.
+func predicateNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
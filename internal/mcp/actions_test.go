@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
 )
 
 func TestInsertAndRemoveTriple(t *testing.T) {
@@ -380,4 +381,680 @@ func TestDescribeEntity(t *testing.T) {
 	if !strings.Contains(text, "No information found for entity") {
 		t.Fatalf("Expected 'No information found' message, got: %s", text)
 	}
-}
\ No newline at end of file
+}
+// newCallToolRequest builds a request calling the named tool with the given arguments.
+func newCallToolRequest(name string, arguments map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	return request
+}
+
+func TestInsertTypedLiteral(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"predicate":            "first_released",
+		"object":               "1991",
+		"object_type":          "integer",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to insert typed literal: %v %v", err, result)
+	}
+
+	result, err = InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"predicate":            "first_released",
+		"object":               "early nineties",
+		"object_type":          "integer",
+	}))
+	if err != nil {
+		t.Fatalf("InsertTripleHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error for an invalid integer literal")
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("Failed to read the knowledge graph: %v", err)
+	}
+	predicates, err := g.PredicatesFromTo("Python", "1991", false)
+	if err != nil {
+		t.Fatalf("Failed to query the predicates: %v", err)
+	}
+	if len(predicates) != 1 {
+		t.Fatalf("Expected 1 predicate, got %d", len(predicates))
+	}
+	if !predicates[0].T.(*kg.Node).IsLiteral() {
+		t.Fatalf("Expected the object to be a literal value")
+	}
+	if g.FindNode("1991", false) != nil {
+		t.Fatalf("The literal value should not be an entity")
+	}
+}
+
+func TestFindTriplesWithFilter(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for subject, year := range map[string]string{"Python": "1991", "Go": "2009", "C": "1972"} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              subject,
+			"predicate":            "first_released",
+			"object":               year,
+			"object_type":          "integer",
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"predicate":            "first_released",
+		"filter":               "> 1990",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if strings.Count(text, "- (") != 2 || strings.Contains(text, "1972") {
+		t.Fatalf("Expected the triples released after 1990, got: %s", text)
+	}
+	if !strings.Contains(text, `"2009"^^integer`) {
+		t.Fatalf("Expected typed literal values in the result, got: %s", text)
+	}
+
+	// Comparing integers with a date is an error
+	result, err = FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"predicate":            "first_released",
+		"filter":               "> 2000-01-01",
+	}))
+	if err != nil {
+		t.Fatalf("FindTriplesHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected a type mismatch error, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestNamedGraphTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for object, graph := range map[string]string{"Alice": "default", "Bob": "project-a"} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              "Python",
+			"predicate":            "used_by",
+			"object":               object,
+			"graph":                graph,
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"graphs":               "project-a",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if strings.Count(text, "- (") != 1 || !strings.Contains(text, "(Python, used_by, Bob) in graph project-a") {
+		t.Fatalf("Expected the triple of project-a only, got: %s", text)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Python",
+		"graphs":               "default",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	text = result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Alice") || strings.Contains(text, "Bob") {
+		t.Fatalf("Expected the triples of the default graph only, got: %s", text)
+	}
+
+	result, err = RemoveTripleHandler(ctx, newCallToolRequest("remove_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"predicate":            "used_by",
+		"object":               "Bob",
+		"graphs":               "default",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RemoveTripleHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Triple not found." {
+		t.Fatalf("The triple should not be found in the default graph, got: %s", text)
+	}
+}
+
+func TestFindTriplesWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"predicate":            "created_by",
+		"object":               "Guido",
+		"source":               "chat-42",
+		"agent":                "assistant",
+		"confidence":           0.9,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to insert test triple: %v %v", err, result)
+	}
+
+	result, err = FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; strings.Contains(text, "chat-42") {
+		t.Fatalf("The metadata should only be returned when asked, got: %s", text)
+	}
+
+	result, err = FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"include_metadata":     true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, expected := range []string{"created ", "source chat-42", "agent assistant", "confidence 0.9"} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("Expected %q in the result, got: %s", expected, text)
+		}
+	}
+
+	result, err = InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"predicate":            "created_by",
+		"object":               "Guido",
+		"confidence":           2.0,
+	}))
+	if err != nil {
+		t.Fatalf("InsertTripleHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error for a confidence above 1")
+	}
+}
+
+func TestRetractSource(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][4]string{
+		{"Python", "created_by", "Guido", "doc-1"},
+		{"Guido", "born_in", "Haarlem", "doc-1"},
+		{"Python", "is_a", "Language", "doc-2"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+			"source":               triple[3],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := RetractSourceHandler(ctx, newCallToolRequest("retract_source", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"source":               "doc-1",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RetractSourceHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Removed 2 triples and 2 nodes") {
+		t.Fatalf("Expected the removal counts, got: %s", text)
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("Failed to read the knowledge graph: %v", err)
+	}
+	if triples := g.FindTriples("", "", "", false); len(triples) != 1 {
+		t.Fatalf("Expected only the triple of doc-2 to remain, got: %v", triples)
+	}
+	if g.FindNode("Haarlem", false) != nil {
+		t.Fatalf("Orphaned nodes should be removed")
+	}
+}
+
+func TestMergeEntitiesTool(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Go", "created_by", "Google"},
+		{"Golang", "created_by", "Google"},
+		{"Golang", "has_mascot", "Gopher"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := MergeEntitiesHandler(ctx, newCallToolRequest("merge_entities", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"keep":                 "Go",
+		"drop":                 "Golang",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("MergeEntitiesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "1 triples moved, 1 duplicate triples removed") {
+		t.Fatalf("Expected the merge counts, got: %s", text)
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("Failed to read the knowledge graph: %v", err)
+	}
+	node := g.FindNode("golang", false)
+	if node == nil || node.Lexical != "Go" {
+		t.Fatalf("Expected Golang to resolve to Go, got: %v", node)
+	}
+
+	result, err = MergeEntitiesHandler(ctx, newCallToolRequest("merge_entities", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"keep":                 "Go",
+		"drop":                 "Rust",
+	}))
+	if err != nil {
+		t.Fatalf("MergeEntitiesHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error when merging an unknown entity")
+	}
+}
+
+func TestAliasTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Go",
+		"predicate":            "created_by",
+		"object":               "Google",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to insert test triple: %v %v", err, result)
+	}
+
+	result, err = AddAliasHandler(ctx, newCallToolRequest("add_alias", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Go",
+		"alias":                "Golang",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("AddAliasHandler failed: %v %v", err, result)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "golang",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Entity: Go\n") || !strings.Contains(text, "Also known as: Golang") || !strings.Contains(text, "- Go created_by Google") {
+		t.Fatalf("Expected the alias to resolve to Go, got: %s", text)
+	}
+
+	readRequest := mcp.ReadResourceRequest{}
+	readRequest.Params.URI = "graph://" + kgPath + "?from=Golang&to=Google"
+	readRequest.Params.Arguments = map[string]interface{}{
+		"knowledge_graph_path": []string{kgPath},
+		"from_subject":         []string{"Golang"},
+		"to_subject":           []string{"Google"},
+	}
+	contents, err := GetRelationFromToHandler(ctx, readRequest)
+	if err != nil {
+		t.Fatalf("GetRelationFromToHandler failed: %v", err)
+	}
+	if len(contents) != 1 || contents[0].(mcp.TextResourceContents).Text != "created_by" {
+		t.Fatalf("Expected the created_by relation through the alias, got: %v", contents)
+	}
+
+	result, err = RemoveAliasHandler(ctx, newCallToolRequest("remove_alias", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Go",
+		"alias":                "Golang",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RemoveAliasHandler failed: %v %v", err, result)
+	}
+
+	result, err = RemoveAliasHandler(ctx, newCallToolRequest("remove_alias", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Go",
+		"alias":                "Golang",
+	}))
+	if err != nil {
+		t.Fatalf("RemoveAliasHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error when removing an unknown alias")
+	}
+}
+
+func TestEntityKeysDisambiguation(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, arguments := range []map[string]interface{}{
+		{"subject": "Paris", "subject_key": "geo:paris-fr", "predicate": "located_in", "object": "France"},
+		{"subject": "Paris", "subject_key": "geo:paris-tx", "predicate": "located_in", "object": "Texas"},
+	} {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", arguments))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Paris",
+	}))
+	if err != nil {
+		t.Fatalf("DescribeEntityHandler failed: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !result.IsError || !strings.Contains(text, "geo:paris-fr") || !strings.Contains(text, "geo:paris-tx") {
+		t.Fatalf("Expected a disambiguation error listing both keys, got: %s", text)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "geo:paris-tx",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	text = result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Key: geo:paris-tx") || !strings.Contains(text, "Texas") || strings.Contains(text, "France") {
+		t.Fatalf("Expected the description of Paris, Texas only, got: %s", text)
+	}
+
+	result, err = RemoveTripleHandler(ctx, newCallToolRequest("remove_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Paris",
+		"predicate":            "located_in",
+		"object":               "Texas",
+	}))
+	if err != nil {
+		t.Fatalf("RemoveTripleHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected a disambiguation error, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestRenameTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Pyhton", "creatd_by", "Guido"},
+		{"Django", "written_in", "Pyhton"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := RenameEntityHandler(ctx, newCallToolRequest("rename_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"old_name":             "Pyhton",
+		"new_name":             "Python",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RenameEntityHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "2 triples changed") {
+		t.Fatalf("Expected 2 triples changed, got: %s", text)
+	}
+
+	result, err = RenamePredicateHandler(ctx, newCallToolRequest("rename_predicate", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"old_name":             "creatd_by",
+		"new_name":             "created_by",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RenamePredicateHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "1 triples changed") {
+		t.Fatalf("Expected 1 triple changed, got: %s", text)
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("Failed to read the knowledge graph: %v", err)
+	}
+	if triples := g.FindTriples("Python", "created_by", "Guido", false); len(triples) != 1 {
+		t.Fatalf("Expected the renamed triple, got: %v", triples)
+	}
+
+	result, err = RenamePredicateHandler(ctx, newCallToolRequest("rename_predicate", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"old_name":             "unknown",
+		"new_name":             "known",
+	}))
+	if err != nil {
+		t.Fatalf("RenamePredicateHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error when renaming an unknown predicate")
+	}
+}
+
+func TestDeleteEntity(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Python", "created_by", "Guido"},
+		{"Django", "written_in", "Python"},
+		{"Django", "is_a", "Framework"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to insert test triple: %v %v", err, result)
+		}
+	}
+
+	result, err := DeleteEntityHandler(ctx, newCallToolRequest("delete_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Python",
+		"remove_orphans":       true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DeleteEntityHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "2 triples") {
+		t.Fatalf("Expected 2 triples deleted, got: %s", text)
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("Failed to read the knowledge graph: %v", err)
+	}
+	if nodes := g.ListNodes(); len(nodes) != 2 {
+		t.Fatalf("Expected only Django and Framework to remain, got: %v", nodes)
+	}
+
+	result, err = RemoveTripleHandler(ctx, newCallToolRequest("remove_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Django",
+		"predicate":            "is_a",
+		"object":               "Framework",
+		"remove_orphans":       true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RemoveTripleHandler failed: %v %v", err, result)
+	}
+	g, err = ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("Failed to read the knowledge graph: %v", err)
+	}
+	if nodes := g.ListNodes(); len(nodes) != 0 {
+		t.Fatalf("Expected the orphans to be removed, got: %v", nodes)
+	}
+}
+
+func TestListInstances(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Python", "rdf:type", "Interpreted Language"},
+		{"Go", "rdf:type", "Compiled Language"},
+		{"Interpreted Language", "rdfs:subClassOf", "Programming Language"},
+		{"Compiled Language", "rdfs:subClassOf", "Programming Language"},
+		{"Python", "created_by", "Guido van Rossum"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	result, err := ListInstancesHandler(ctx, newCallToolRequest("list_instances", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"class":                "programming language",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("ListInstancesHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "(2)") || !strings.Contains(text, "- Go (Compiled Language)\n- Python (Interpreted Language)") {
+		t.Fatalf("Expected Go and Python as instances of their subclasses, got: %s", text)
+	}
+
+	result, err = ListInstancesHandler(ctx, newCallToolRequest("list_instances", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"class":                "Person",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("ListInstancesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "No instances found") {
+		t.Fatalf("Expected no instances, got: %s", text)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Python",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	text = result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Types: Interpreted Language\n") || !strings.Contains(text, "Inherited types: Programming Language\n") {
+		t.Fatalf("Expected the types of Python, got: %s", text)
+	}
+}
+
+func TestFindTriplesPagination(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, language := range []string{"Python", "go", "C", "Rust", "Java"} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              language,
+			"predicate":            "is_a",
+			"object":               "Programming Language",
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	result, err := FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"predicate":            "is_a",
+		"limit":                float64(2),
+		"offset":               float64(1),
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+	}
+	expected := "Found triples (5 in total, showing 2 to 3):\n- (go, is_a, Programming Language)\n- (Java, is_a, Programming Language)\n"
+	if text := result.Content[0].(mcp.TextContent).Text; text != expected {
+		t.Fatalf("Expected the second page in a case-insensitive order, got: %s", text)
+	}
+
+	result, err = FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"sort_by":              "name",
+	}))
+	if err != nil || !result.IsError {
+		t.Fatalf("Expected an error for an unknown sort order: %v %v", err, result)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Programming Language",
+		"sort_by":              "created",
+		"descending":           true,
+		"limit":                float64(1),
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Triples: 5 in total, showing 1 to 1\n") || !strings.Contains(text, "- Java is_a Programming Language\n") || strings.Contains(text, "Rust") {
+		t.Fatalf("Expected the last inserted triple only, got: %s", text)
+	}
+}
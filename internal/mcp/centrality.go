@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func RankEntities() mcp.Tool {
+	return mcp.NewTool(
+		"rank_entities",
+		mcp.WithDescription("Get the most central entities of the knowledge graph, its hubs, ranked by PageRank, degree or betweenness centrality; only the triples between entities are taken into account"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("measure",
+			mcp.Description("the measure to rank by: pagerank (default), degree, in_degree, out_degree, or betweenness (the number of shortest paths between other entities going through the entity, slower on large graphs)"),
+			mcp.Enum(string(kg.MeasurePageRank), string(kg.MeasureDegree), string(kg.MeasureInDegree), string(kg.MeasureOutDegree), string(kg.MeasureBetweenness)),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("the number of entities to return (default 10)"),
+			mcp.Min(1),
+		),
+		mcp.WithString("predicates",
+			mcp.Description("a comma-separated list of the predicates of the triples taken into account (leave empty to take every predicate into account)"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs whose triples are taken into account, default naming the default graph (leave empty to take every graph into account)"),
+		),
+	)
+}
+
+func RankEntitiesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Extract the optional parameters
+	measure := kg.MeasurePageRank
+	if val, ok := request.Params.Arguments["measure"]; ok && val != nil {
+		measure = kg.Measure(val.(string))
+	}
+	limit := 10
+	if val, ok := request.Params.Arguments["limit"]; ok && val != nil {
+		limit = int(val.(float64))
+	}
+	var options kg.CentralityOptions
+	if val, ok := request.Params.Arguments["predicates"]; ok && val != nil {
+		options.Predicates = predicateNames(val.(string))
+	}
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		options.Graphs = graphNames(val.(string))
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking, err := g.RankEntities(measure, limit, options, false)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	var result string
+	if len(ranking) == 0 {
+		result = "No entities found"
+	} else {
+		result = fmt.Sprintf("Top %d entities by %s:\n", len(ranking), measure)
+		for i, centrality := range ranking {
+			result += fmt.Sprintf("%d. %s (", i+1, centrality.Entity.Lexical)
+			switch measure {
+			case kg.MeasurePageRank:
+				result += fmt.Sprintf("pagerank %.4f, ", centrality.PageRank)
+			case kg.MeasureBetweenness:
+				result += fmt.Sprintf("betweenness %.2f, ", centrality.Betweenness)
+			}
+			result += fmt.Sprintf("in-degree %d, out-degree %d)\n", centrality.InDegree, centrality.OutDegree)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRankEntities(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Alice", "knows", "Bob"},
+		{"Alice", "knows", "Carol"},
+		{"Alice", "knows", "Dave"},
+		{"Dave", "works_for", "Acme"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	rank := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := RankEntitiesHandler(ctx, newCallToolRequest("rank_entities", arguments))
+		if err != nil {
+			t.Fatalf("RankEntitiesHandler failed: %v", err)
+		}
+		return result
+	}
+
+	result := rank(map[string]interface{}{"measure": "degree", "limit": float64(2)})
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.HasPrefix(text, "Top 2 entities by degree:\n1. Alice (in-degree 0, out-degree 3)\n2. Dave (") {
+		t.Fatalf("Expected Alice and Dave ranked by degree, got: %s", text)
+	}
+
+	result = rank(map[string]interface{}{"measure": "betweenness", "limit": float64(1)})
+	text = result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.HasPrefix(text, "Top 1 entities by betweenness:\n1. Alice (betweenness 5.00, ") {
+		t.Fatalf("Expected Alice ranked by betweenness, got: %s", text)
+	}
+
+	result = rank(map[string]interface{}{})
+	text = result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.HasPrefix(text, "Top 5 entities by pagerank:\n1. Acme (pagerank ") {
+		t.Fatalf("Expected Acme ranked first by PageRank, got: %s", text)
+	}
+
+	result = rank(map[string]interface{}{"measure": "in_degree", "predicates": "works_for", "limit": float64(1)})
+	text = result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.Contains(text, "1. Acme (") {
+		t.Fatalf("Expected Acme ranked first for works_for, got: %s", text)
+	}
+
+	result = rank(map[string]interface{}{"measure": "fame"})
+	if !result.IsError {
+		t.Fatalf("Expected an error for an unknown measure")
+	}
+}
@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+// maxListedMembers is the number of members listed for each community, the most representative first.
+const maxListedMembers = 10
+
+func DetectCommunities() mcp.Tool {
+	return mcp.NewTool(
+		"detect_communities",
+		mcp.WithDescription("Find the topical clusters of the knowledge graph: groups of entities more densely linked to each other than to the rest of the graph, each named after its most representative entity. The memberships can be written back into the graph as triples"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("predicates",
+			mcp.Description("a comma-separated list of the predicates of the triples taken into account (leave empty to take every predicate into account)"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs whose triples are taken into account, default naming the default graph (leave empty to take every graph into account)"),
+		),
+		mcp.WithNumber("resolution",
+			mcp.Description("the granularity of the communities: higher values give more and smaller communities (default 1)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("the number of communities to list, the largest first (default 10)"),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("write",
+			mcp.Description("whether to record the community of each entity as a triple, replacing the memberships recorded before"),
+		),
+		mcp.WithString("membership_predicate",
+			mcp.Description("the predicate of the membership triples, whose object is the name of the community (default community)"),
+		),
+		mcp.WithString("graph",
+			mcp.Description("the name of the graph holding the membership triples (leave empty or use default for the default graph)"),
+		),
+	)
+}
+
+func DetectCommunitiesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Extract the optional parameters
+	var options kg.CommunityOptions
+	if val, ok := request.Params.Arguments["predicates"]; ok && val != nil {
+		options.Predicates = predicateNames(val.(string))
+	}
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		options.Graphs = graphNames(val.(string))
+	}
+	if val, ok := request.Params.Arguments["resolution"]; ok && val != nil {
+		options.Resolution = val.(float64)
+	}
+	limit := 10
+	if val, ok := request.Params.Arguments["limit"]; ok && val != nil {
+		limit = int(val.(float64))
+	}
+	var write bool
+	if val, ok := request.Params.Arguments["write"]; ok && val != nil {
+		write = val.(bool)
+	}
+	predicate := "community"
+	if val, ok := request.Params.Arguments["membership_predicate"]; ok && val != nil && val.(string) != "" {
+		predicate = val.(string)
+	}
+	var graph string
+	if val, ok := request.Params.Arguments["graph"]; ok && val != nil {
+		graph = graphName(val.(string))
+	}
+
+	var clustering *kg.Clustering
+	var recorded int
+	var err error
+	if write {
+		// Use the file-safe modifier function
+		err = ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+			var err error
+			clustering, err = g.Communities(options, false)
+			if err != nil {
+				return err
+			}
+			recorded, err = g.AssignCommunities(clustering, predicate, graph)
+			return err
+		})
+	} else {
+		// Read the graph using the thread-safe method
+		var g *kg.KG
+		g, err = ReadKnowledgeGraph(graphPath)
+		if err != nil {
+			return nil, err
+		}
+		clustering, err = g.Communities(options, false)
+	}
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	// List the communities of several entities, the largest first
+	var communities []kg.Community
+	for _, c := range clustering.Communities {
+		if len(c.Members) > 1 {
+			communities = append(communities, c)
+		}
+	}
+	alone := len(clustering.Communities) - len(communities)
+
+	var result string
+	if len(communities) == 0 {
+		result = "No communities found: no entities are linked to each other.\n"
+	} else {
+		result = fmt.Sprintf("Found %d communities (modularity %.2f):\n", len(communities), clustering.Modularity)
+		for i, c := range communities {
+			if i == limit {
+				result += fmt.Sprintf("... and %d smaller communities; raise limit to see them.\n", len(communities)-limit)
+				break
+			}
+			result += fmt.Sprintf("%d. %s (%d entities): %s\n", i+1, c.Name, len(c.Members), listEntities(c.Members, maxListedMembers))
+		}
+	}
+	if alone > 0 {
+		result += fmt.Sprintf("%d entities belong to no community.\n", alone)
+	}
+	if write {
+		result += fmt.Sprintf("Recorded the community of %d entities with the predicate %s.\n", recorded, predicate)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
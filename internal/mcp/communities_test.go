@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDetectCommunities(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Alice", "knows", "Bob"},
+		{"Alice", "knows", "Carol"},
+		{"Bob", "knows", "Carol"},
+		{"Bob", "likes", "Carol"},
+		{"Dave", "knows", "Eve"},
+		{"Dave", "knows", "Frank"},
+		{"Eve", "knows", "Frank"},
+		{"Carol", "knows", "Dave"},
+		{"Zed", "knows", "Zed"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	detect := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := DetectCommunitiesHandler(ctx, newCallToolRequest("detect_communities", arguments))
+		if err != nil {
+			t.Fatalf("DetectCommunitiesHandler failed: %v", err)
+		}
+		return result
+	}
+
+	result := detect(map[string]interface{}{})
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.HasPrefix(text, "Found 2 communities (modularity ") ||
+		!strings.Contains(text, "1. Bob (3 entities): Bob, Carol, Alice\n2. Dave (3 entities): Dave, Eve, Frank\n1 entities belong to no community.\n") {
+		t.Fatalf("Expected the communities of Bob and Dave, got: %s", text)
+	}
+
+	result = detect(map[string]interface{}{"limit": float64(1), "write": true, "membership_predicate": "cluster"})
+	text = result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.Contains(text, "... and 1 smaller communities") || !strings.Contains(text, "Recorded the community of 6 entities with the predicate cluster.") {
+		t.Fatalf("Expected the memberships to be recorded, got: %s", text)
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("ReadKnowledgeGraph failed: %v", err)
+	}
+	if triples := g.FindTriples("Eve", "cluster", "", true); len(triples) != 1 || triples[0][2] != "Dave" {
+		t.Fatalf("Expected Eve to be in the community of Dave, got: %v", triples)
+	}
+	if g.Revision() != 10 {
+		t.Fatalf("Expected the memberships to be recorded as a revision, got revision %d", g.Revision())
+	}
+
+	result = detect(map[string]interface{}{"resolution": float64(-1)})
+	if !result.IsError {
+		t.Fatalf("Expected an error for a negative resolution")
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func GraphHealth() mcp.Tool {
+	return mcp.NewTool(
+		"graph_health",
+		mcp.WithDescription("Check whether the knowledge graph holds together: its connected components and their sizes, the entities linked to no other entity, the orphan entities without any triple, and the triples linking an entity to itself"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("predicates",
+			mcp.Description("a comma-separated list of the predicates of the triples taken into account (leave empty to take every predicate into account)"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs whose triples are taken into account, default naming the default graph (leave empty to take every graph into account)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("the number of components, entities and self-loops to list in each part of the report (default 10)"),
+			mcp.Min(1),
+		),
+	)
+}
+
+func GraphHealthHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Extract the optional parameters
+	var options kg.ComponentOptions
+	if val, ok := request.Params.Arguments["predicates"]; ok && val != nil {
+		options.Predicates = predicateNames(val.(string))
+	}
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		options.Graphs = graphNames(val.(string))
+	}
+	limit := 10
+	if val, ok := request.Params.Arguments["limit"]; ok && val != nil {
+		limit = int(val.(float64))
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := g.Health(options, false)
+	result := fmt.Sprintf("Entities: %d, triples between entities: %d\n", report.Entities, report.Triples)
+	if report.Entities > 0 {
+		result += fmt.Sprintf("\nWeakly connected components: %d (largest: %d entities)\n", len(report.WeakComponents), len(report.WeakComponents[0]))
+		if len(report.WeakComponents) == 1 {
+			result += "The graph is connected.\n"
+		}
+		result += listComponents(report.WeakComponents, limit)
+		result += fmt.Sprintf("\nStrongly connected components: %d (largest: %d entities)\n", len(report.StrongComponents), len(report.StrongComponents[0]))
+		result += listComponents(report.StrongComponents, limit)
+	}
+
+	result += fmt.Sprintf("\nIsolated entities, linked to no other entity (%d): %s\n", len(report.Isolated), listEntities(report.Isolated, limit))
+	result += fmt.Sprintf("Orphan entities, without any triple (%d): %s\n", len(report.Orphans), listEntities(report.Orphans, limit))
+
+	result += fmt.Sprintf("\nSelf-loops (%d):\n", len(report.SelfLoops))
+	for i, triple := range report.SelfLoops {
+		if i == limit {
+			result += fmt.Sprintf("- ... and %d more\n", len(report.SelfLoops)-limit)
+			break
+		}
+		result += "- (" + triple.F.(*kg.Node).String() + ", " + triple.Subject + ", " + triple.T.(*kg.Node).String() + ")"
+		if triple.Graph != kg.DefaultGraph {
+			result += " in graph " + triple.Graph
+		}
+		result += "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// listComponents lists the components of several entities, at most limit of them, one per line.
+func listComponents(components [][]*kg.Node, limit int) string {
+	var result string
+	listed := 0
+	for _, component := range components {
+		if len(component) < 2 {
+			continue
+		}
+		if listed == limit {
+			result += "- ... and smaller components\n"
+			break
+		}
+		result += fmt.Sprintf("- %d entities: %s\n", len(component), listEntities(component, maxListedMembers))
+		listed++
+	}
+	return result
+}
+
+// listEntities returns the labels of at most limit entities separated by commas, "none" if there are no entities.
+func listEntities(entities []*kg.Node, limit int) string {
+	if len(entities) == 0 {
+		return "none"
+	}
+	var labels []string
+	for _, entity := range entities[:min(limit, len(entities))] {
+		labels = append(labels, entity.Lexical)
+	}
+	result := strings.Join(labels, ", ")
+	if more := len(entities) - len(labels); more > 0 {
+		result += fmt.Sprintf(" and %d more", more)
+	}
+	return result
+}
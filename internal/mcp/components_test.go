@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestGraphHealth(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Alice", "knows", "Bob"},
+		{"Bob", "knows", "Carol"},
+		{"Carol", "knows", "Alice"},
+		{"Carol", "works_for", "Acme"},
+		{"Dave", "knows", "Eve"},
+		{"Zed", "knows", "Zed"},
+		{"Xavier", "knows", "Alice"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+	result, err := RemoveTripleHandler(ctx, newCallToolRequest("remove_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Xavier",
+		"predicate":            "knows",
+		"object":               "Alice",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RemoveTripleHandler failed: %v %v", err, result)
+	}
+
+	check := func(arguments map[string]interface{}) string {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := GraphHealthHandler(ctx, newCallToolRequest("graph_health", arguments))
+		if err != nil || result.IsError {
+			t.Fatalf("GraphHealthHandler failed: %v %v", err, result)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	expected := "Entities: 8, triples between entities: 6\n" +
+		"\nWeakly connected components: 4 (largest: 4 entities)\n" +
+		"- 4 entities: Acme, Alice, Bob, Carol\n" +
+		"- 2 entities: Dave, Eve\n" +
+		"\nStrongly connected components: 6 (largest: 3 entities)\n" +
+		"- 3 entities: Alice, Bob, Carol\n" +
+		"\nIsolated entities, linked to no other entity (2): Xavier, Zed\n" +
+		"Orphan entities, without any triple (1): Xavier\n" +
+		"\nSelf-loops (1):\n" +
+		"- (Zed, knows, Zed)\n"
+	if text := check(map[string]interface{}{}); text != expected {
+		t.Fatalf("Expected the health report of the graph, got: %s", text)
+	}
+
+	expected = "Entities: 8, triples between entities: 1\n" +
+		"\nWeakly connected components: 7 (largest: 2 entities)\n" +
+		"- 2 entities: Acme, Carol\n" +
+		"\nStrongly connected components: 8 (largest: 1 entities)\n" +
+		"\nIsolated entities, linked to no other entity (6): Alice and 5 more\n" +
+		"Orphan entities, without any triple (1): Xavier\n" +
+		"\nSelf-loops (0):\n"
+	if text := check(map[string]interface{}{"predicates": "works_for", "limit": float64(1)}); text != expected {
+		t.Fatalf("Expected the health report of the works_for triples, got: %s", text)
+	}
+}
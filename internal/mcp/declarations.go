@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func DeclarePredicate() mcp.Tool {
+	return mcp.NewTool(
+		"declare_predicate",
+		mcp.WithDescription("Declare properties of a predicate: its inverse or whether it is symmetric, so that facts are answered in both directions by describe_entity and the graph resources without being stored twice, and how many values a subject may have for it"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("predicate",
+			mcp.Required(),
+			mcp.Description("the predicate to declare, such as parent_of"),
+		),
+		mcp.WithString("inverse",
+			mcp.Description("the predicate stating the same fact from the object to the subject, such as child_of for parent_of"),
+		),
+		mcp.WithBoolean("symmetric",
+			mcp.Description("whether the predicate states the same fact in both directions, as married_to does"),
+		),
+		mcp.WithBoolean("functional",
+			mcp.Description("whether a subject may have only one value for the predicate, as for born_in; same as max_values 1"),
+		),
+		mcp.WithNumber("max_values",
+			mcp.Description("the maximum number of distinct values a subject may have for the predicate, 0 for no limit"),
+			mcp.Min(0),
+		),
+		mcp.WithString("on_conflict",
+			mcp.Description("what happens when a triple gives a subject more values than allowed: reject the triple (default), replace the oldest values, or flag the conflict and keep both, to be reported by find_contradictions"),
+			mcp.Enum(string(kg.ConflictReject), string(kg.ConflictReplace), string(kg.ConflictFlag)),
+		),
+		mcp.WithBoolean("remove",
+			mcp.Description("whether to remove every property declared for the predicate instead"),
+		),
+	)
+}
+
+func DeclarePredicateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	predicate := request.Params.Arguments["predicate"].(string)
+
+	// Extract the optional properties
+	var inverse string
+	if val, ok := request.Params.Arguments["inverse"]; ok && val != nil {
+		inverse = val.(string)
+	}
+	symmetric := false
+	if val, ok := request.Params.Arguments["symmetric"]; ok && val != nil {
+		symmetric = val.(bool)
+	}
+	maxValues := -1
+	if val, ok := request.Params.Arguments["max_values"]; ok && val != nil {
+		maxValues = int(val.(float64))
+	}
+	if val, ok := request.Params.Arguments["functional"]; ok && val != nil && val.(bool) {
+		maxValues = 1
+	}
+	var onConflict kg.ConflictPolicy
+	if val, ok := request.Params.Arguments["on_conflict"]; ok && val != nil {
+		onConflict = kg.ConflictPolicy(val.(string))
+	}
+	remove := false
+	if val, ok := request.Params.Arguments["remove"]; ok && val != nil {
+		remove = val.(bool)
+	}
+
+	// Use the file-safe modifier function
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		if remove {
+			return g.RemoveDeclaration(predicate)
+		}
+		if !symmetric && inverse == "" && maxValues < 0 {
+			return fmt.Errorf("nothing to declare for predicate %q: give an inverse, symmetric, functional, max_values or remove", predicate)
+		}
+
+		if symmetric {
+			if err := g.DeclareSymmetric(predicate); err != nil {
+				return err
+			}
+		}
+		if inverse != "" {
+			if err := g.DeclareInverse(predicate, inverse); err != nil {
+				return err
+			}
+		}
+		if maxValues >= 0 {
+			return g.DeclareCardinality(predicate, maxValues, onConflict)
+		}
+		return nil
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "success",
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func ListPredicateDeclarations() mcp.Tool {
+	return mcp.NewTool(
+		"list_predicate_declarations",
+		mcp.WithDescription("List the properties declared for the predicates of the knowledge graph, such as their inverse, whether they are symmetric and how many values a subject may have"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+	)
+}
+
+func ListPredicateDeclarationsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	declarations := g.Declarations()
+	if len(declarations) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "No predicate declarations found.",
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	result := "Predicate declarations:\n"
+	for _, declaration := range declarations {
+		result += "- " + declaration.Name
+		if declaration.Symmetric {
+			result += " is symmetric"
+		}
+		if declaration.Inverse != "" {
+			result += " has inverse " + declaration.Inverse
+		}
+		if declaration.Functional() {
+			result += " is functional (on conflict: " + string(declaration.OnConflict) + ")"
+		} else if declaration.MaxValues > 0 {
+			result += fmt.Sprintf(" allows %d values (on conflict: %s)", declaration.MaxValues, declaration.OnConflict)
+		}
+		result += "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func FindContradictions() mcp.Tool {
+	return mcp.NewTool(
+		"find_contradictions",
+		mcp.WithDescription("List the subjects having more values for a predicate than declared with declare_predicate, such as two birth places for a functional born_in"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("subject",
+			mcp.Description("the subject to check (leave empty to check every subject)"),
+		),
+	)
+}
+
+func FindContradictionsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	var subject string
+	if val, ok := request.Params.Arguments["subject"]; ok && val != nil {
+		subject = val.(string)
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	contradictions := g.FindContradictions(subject, false)
+	if len(contradictions) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "No contradictions found.",
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	result := "Found contradictions:\n"
+	for _, contradiction := range contradictions {
+		result += "- " + contradiction.String() + "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
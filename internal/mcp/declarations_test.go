@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDeclarePredicate(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Alice",
+		"predicate":            "parent_of",
+		"object":               "Bob",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+	}
+
+	declare := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := DeclarePredicateHandler(ctx, newCallToolRequest("declare_predicate", arguments))
+		if err != nil {
+			t.Fatalf("DeclarePredicateHandler failed: %v", err)
+		}
+		return result
+	}
+
+	if result := declare(map[string]interface{}{"predicate": "parent_of", "inverse": "child_of"}); result.IsError {
+		t.Fatalf("Failed to declare an inverse: %v", result.Content)
+	}
+	if result := declare(map[string]interface{}{"predicate": "married_to", "symmetric": true}); result.IsError {
+		t.Fatalf("Failed to declare a symmetric predicate: %v", result.Content)
+	}
+	if result := declare(map[string]interface{}{"predicate": "child_of", "inverse": "descendant_of"}); !result.IsError {
+		t.Fatalf("Expected an error for a second inverse")
+	}
+	if result := declare(map[string]interface{}{"predicate": "child_of"}); !result.IsError {
+		t.Fatalf("Expected an error when nothing is declared")
+	}
+
+	result, err = ListPredicateDeclarationsHandler(ctx, newCallToolRequest("list_predicate_declarations", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("ListPredicateDeclarationsHandler failed: %v %v", err, result)
+	}
+	expected := "- child_of has inverse parent_of\n- married_to is symmetric\n- parent_of has inverse child_of\n"
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, expected) {
+		t.Fatalf("Expected the declarations, got: %s", text)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Bob",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "- Bob child_of Alice\n") || !strings.Contains(text, "- Alice parent_of Bob\n") {
+		t.Fatalf("Expected the fact in both directions, got: %s", text)
+	}
+
+	if result := declare(map[string]interface{}{"predicate": "parent_of", "remove": true}); result.IsError {
+		t.Fatalf("Failed to remove a declaration: %v", result.Content)
+	}
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Bob",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; strings.Contains(text, "child_of") {
+		t.Fatalf("Expected the inverse to be gone, got: %s", text)
+	}
+}
+
+func TestFindContradictions(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	insert := func(subject, object string) *mcp.CallToolResult {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              subject,
+			"predicate":            "born_in",
+			"object":               object,
+		}))
+		if err != nil {
+			t.Fatalf("InsertTripleHandler failed: %v", err)
+		}
+		return result
+	}
+
+	insert("Alice", "Paris")
+	result, err := DeclarePredicateHandler(ctx, newCallToolRequest("declare_predicate", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"predicate":            "born_in",
+		"functional":           true,
+		"on_conflict":          "flag",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DeclarePredicateHandler failed: %v %v", err, result)
+	}
+
+	result = insert("Alice", "Lyon")
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError || text != "success\nwarning: Alice has 2 values for born_in, which allows 1: Paris, Lyon" {
+		t.Fatalf("Expected the flagged conflict as a warning, got: %s", text)
+	}
+
+	result, err = FindContradictionsHandler(ctx, newCallToolRequest("find_contradictions", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindContradictionsHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "- Alice has 2 values for born_in, which allows 1: Paris, Lyon\n") {
+		t.Fatalf("Expected the contradiction, got: %s", text)
+	}
+
+	result, err = DeclarePredicateHandler(ctx, newCallToolRequest("declare_predicate", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"predicate":            "born_in",
+		"max_values":           float64(1),
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DeclarePredicateHandler failed: %v %v", err, result)
+	}
+	if result := insert("Bob", "Rome"); result.IsError {
+		t.Fatalf("Expected the first value to be accepted: %v", result.Content)
+	}
+	if result := insert("Bob", "Milan"); !result.IsError {
+		t.Fatalf("Expected the second value to be rejected")
+	}
+
+	result, err = ListPredicateDeclarationsHandler(ctx, newCallToolRequest("list_predicate_declarations", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("ListPredicateDeclarationsHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "- born_in is functional (on conflict: reject)\n") {
+		t.Fatalf("Expected the declaration, got: %s", text)
+	}
+
+	result, err = FindContradictionsHandler(ctx, newCallToolRequest("find_contradictions", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Bob",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("FindContradictionsHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "No contradictions found." {
+		t.Fatalf("Expected no contradiction for Bob, got: %s", text)
+	}
+}
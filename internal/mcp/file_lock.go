@@ -91,6 +91,7 @@ func WriteKnowledgeGraph(path string, graph *kg.KG) error {
 
 // ModifyKnowledgeGraph safely modifies a knowledge graph and writes it back to the file.
 // It reads the file, applies a modification function, and writes the result back.
+// The changes made by the modification are recorded as a new revision in the history of the graph.
 // The entire operation is protected by a file-level write lock.
 func ModifyKnowledgeGraph(path string, modifier func(*kg.KG) error) error {
 	// Get the file lock
@@ -116,8 +117,8 @@ func ModifyKnowledgeGraph(path string, modifier func(*kg.KG) error) error {
 		graph = kg.NewKG("")
 	}
 
-	// Apply the modification
-	if err := modifier(graph); err != nil {
+	// Apply the modification, recording its changes
+	if _, err := graph.RecordChanges(modifier); err != nil {
 		return err
 	}
 
@@ -39,7 +39,11 @@ func GetRelationFromToHandler(ctx context.Context, request mcp.ReadResourceReque
 		return nil, err
 	}
 
-	predicates := graph.PredicatesFromTo(from, to, false)
+	// Ambiguous entities must be designated by their keys
+	predicates, err := graph.PredicatesFromTo(from, to, false)
+	if err != nil {
+		return nil, err
+	}
 
 	result := make([]mcp.ResourceContents, len(predicates))
 	for i, predicate := range predicates {
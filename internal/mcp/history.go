@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func GraphHistory() mcp.Tool {
+	return mcp.NewTool(
+		"graph_history",
+		mcp.WithDescription("List the latest revisions of the knowledge graph, most recent first, with the time of each revision, the triples and entities it inserted and removed, and the changes of the schema, rules and predicate declarations"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("the maximum number of revisions to list (default 10)"),
+			mcp.Min(1),
+		),
+	)
+}
+
+func GraphHistoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Extract the optional number of revisions
+	limit := 10
+	if val, ok := request.Params.Arguments["limit"]; ok && val != nil {
+		limit = int(val.(float64))
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	history := g.History()
+	if len(history) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "No change recorded.",
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	result := fmt.Sprintf("Current revision: %d\n", g.Revision())
+	for i := len(history) - 1; i >= 0 && i >= len(history)-limit; i-- {
+		result += "\n" + formatRevision(history[i])
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func UndoLastChange() mcp.Tool {
+	return mcp.NewTool(
+		"undo_last_change",
+		mcp.WithDescription("Undo the last revision of the knowledge graph, such as a mistaken insertion or removal, restoring the triples, entities, schema, rules and predicate declarations as they were before it; call it again to undo earlier revisions"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+	)
+}
+
+func UndoLastChangeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Use the file-safe modifier function
+	var undone *kg.Revision
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		var err error
+		undone, err = g.UndoLastChange()
+		return err
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "Undid " + formatRevision(*undone),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// formatRevision describes a revision and its changes, one per line.
+// The literal values inserted and removed with their triples are not listed.
+func formatRevision(revision kg.Revision) string {
+	result := fmt.Sprintf("revision %d (%s):\n", revision.Number, revision.Time.UTC().Format(time.RFC3339))
+	for _, change := range revision.Changes {
+		if change.Node != nil && change.Node.IsLiteral() {
+			continue
+		}
+		result += "- " + change.String() + "\n"
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHistoryTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, object := range []string{"Guido van Rossum", "1991"} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              "Python",
+			"predicate":            "created_by",
+			"object":               object,
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+	result, err := RemoveTripleHandler(ctx, newCallToolRequest("remove_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Python",
+		"predicate":            "created_by",
+		"object":               "Guido van Rossum",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("RemoveTripleHandler failed: %v %v", err, result)
+	}
+
+	result, err = GraphHistoryHandler(ctx, newCallToolRequest("graph_history", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"limit":                float64(2),
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("GraphHistoryHandler failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasPrefix(text, "Current revision: 3\n\nrevision 3 (") ||
+		!strings.Contains(text, "- removed (Python, created_by, Guido van Rossum)\n") ||
+		!strings.Contains(text, "- inserted (Python, created_by, 1991)\n") ||
+		strings.Contains(text, "revision 1") {
+		t.Fatalf("Expected the last two revisions, got: %s", text)
+	}
+
+	result, err = UndoLastChangeHandler(ctx, newCallToolRequest("undo_last_change", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("UndoLastChangeHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.HasPrefix(text, "Undid revision 3 (") {
+		t.Fatalf("Expected the undone revision, got: %s", text)
+	}
+
+	g, err := ReadKnowledgeGraph(kgPath)
+	if err != nil {
+		t.Fatalf("ReadKnowledgeGraph failed: %v", err)
+	}
+	if triples := g.FindTriples("Python", "created_by", "", true); len(triples) != 2 {
+		t.Fatalf("Expected the removed triple to be restored, got: %v", triples)
+	}
+	if revision := g.Revision(); revision != 2 {
+		t.Fatalf("Expected revision 2 after the undo, got %d", revision)
+	}
+
+	for range 2 {
+		result, err = UndoLastChangeHandler(ctx, newCallToolRequest("undo_last_change", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("UndoLastChangeHandler failed: %v %v", err, result)
+		}
+	}
+	result, err = UndoLastChangeHandler(ctx, newCallToolRequest("undo_last_change", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || !result.IsError {
+		t.Fatalf("Expected an error when no change is left to undo: %v %v", err, result)
+	}
+}
@@ -33,6 +33,44 @@ insert_triple(
 )
 
 
+#### Separate Facts in Named Graphs
+
+insert_triple(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  subject="Python", 
+  predicate="used_by", 
+  object="Project A", 
+  graph="project-a"
+)
+
+→ Stores the fact in the graph named project-a; find_triples, describe_entity and remove_triple accept a comma-separated graphs list (default names the default graph)
+
+#### Tell Apart Entities Sharing a Name
+
+insert_triple(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  subject="Paris", 
+  subject_key="geo:paris-texas", 
+  predicate="located_in", 
+  object="Texas"
+)
+
+→ Creates a second Paris identified by the key geo:paris-texas; every tool accepts the key wherever an entity is expected, and an ambiguous name returns an error listing the candidates with their keys
+
+#### Record Where a Fact Comes From
+
+insert_triple(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  subject="Python", 
+  predicate="created_by", 
+  object="Guido van Rossum", 
+  source="https://docs.python.org/3/faq/general.html", 
+  agent="assistant", 
+  confidence=0.95
+)
+
+→ Stores the provenance of the fact; find_triples returns it with include_metadata=true
+
 #### Remove Incorrect Information
 
 remove_triple(
@@ -42,6 +80,54 @@ remove_triple(
   object="Microsoft"
 )
 
+#### Delete an Entity
+
+delete_entity(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  entity="Microsoft Python", 
+  remove_orphans=true
+)
+
+→ Deletes the entity and every triple in which it appears; with remove_orphans, the entities left without any relationship are deleted too (remove_triple accepts the same option)
+
+#### Undo a Bad Ingestion
+
+retract_source(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  source="https://example.com/outdated-article"
+)
+
+→ Removes every triple recorded with this source and the entities left without any relationship
+
+#### Merge Duplicate Entities
+
+merge_entities(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  keep="Guido van Rossum", 
+  drop="G. van Rossum"
+)
+
+→ Moves the relationships of G. van Rossum to Guido van Rossum; G. van Rossum remains usable as an alias
+
+#### Give an Entity Alternate Names
+
+add_alias(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  entity="Go", 
+  alias="Golang"
+)
+
+→ Golang now designates Go in every tool and in graph:// queries; remove_alias takes it back
+
+#### Fix a Misspelled Name
+
+rename_entity(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  old_name="Pyhton", 
+  new_name="Python"
+)
+
+→ Renames the entity in every triple, merging it into Python if Python already exists; rename_predicate does the same for predicates
 
 ### 2. Querying the Knowledge Graph
 
@@ -79,6 +165,109 @@ find_triples(
 
 → Returns all programming languages in the graph
 
+#### Filter Literal Values
+
+find_triples(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  predicate="first_released", 
+  filter="between 1990 and 2000"
+)
+
+→ Returns the entities first released in the nineties (filters also accept =, !=, <, <=, >, >= and prefix)
+
+#### List the Instances of a Class
+
+insert_triple(knowledge_graph_path="/Users/username/knowledge.kg", subject="Python", predicate="rdf:type", object="Interpreted Language")
+insert_triple(knowledge_graph_path="/Users/username/knowledge.kg", subject="Interpreted Language", predicate="rdfs:subClassOf", object="Programming Language")
+
+list_instances(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  class="Programming Language"
+)
+
+→ Returns Python and every other instance of Programming Language or of its subclasses, at any depth
+
+#### Infer Facts with Rules
+
+set_rules(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  rules="is_part_of is transitive\ncites has inverse cited_by\n(?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z)"
+)
+
+find_triples(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  predicate="located_in", 
+  include_inferred=true
+)
+
+→ Returns the asserted located_in triples along with those inferred by the rules, marked with the rule that inferred them; materialize_inferences stores the inferred triples instead of evaluating the rules at each query
+
+#### Answer Facts in Both Directions
+
+declare_predicate(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  predicate="parent_of", 
+  inverse="child_of"
+)
+
+declare_predicate(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  predicate="married_to", 
+  symmetric=true
+)
+
+→ After inserting (Alice, parent_of, Bob), describe_entity for Bob also shows (Bob, child_of, Alice) without storing it twice; list_predicate_declarations shows the declared predicates
+
+#### Detect Contradictions
+
+declare_predicate(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  predicate="born_in", 
+  functional=true, 
+  on_conflict="flag"
+)
+
+find_contradictions(knowledge_graph_path="/Users/username/knowledge.kg")
+
+→ Lists the subjects with several values for born_in; with on_conflict="reject" a second value is refused, and with on_conflict="replace" it replaces the previous one
+
+#### Track How Facts Change Over Time
+
+insert_triple(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  subject="Alice", 
+  predicate="works_for", 
+  object="Acme", 
+  valid_from="2018-01-01"
+)
+
+close_triple(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  subject="Alice", 
+  predicate="works_for", 
+  object="Acme", 
+  valid_to="2021-01-01"
+)
+
+find_triples(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  subject="Alice", 
+  as_of="2019-06-01"
+)
+
+→ Closing a triple keeps it as history instead of deleting it; as_of returns the facts holding at that time, and without it find_triples shows each triple with its validity period. describe_entity accepts as_of as well
+
+#### Review and Undo Changes
+
+graph_history(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  limit=5
+)
+
+undo_last_change(knowledge_graph_path="/Users/username/knowledge.kg")
+
+→ Every change to the knowledge graph is recorded as a revision listing the triples and entities inserted and removed; undo_last_change restores the graph as it was before the last revision, for instance after a mistaken remove_triple
+
 ### 3. Exploring the Knowledge Graph
 
 #### Get Complete Context for an Entity
@@ -88,14 +277,88 @@ describe_entity(
   entity="Python"
 )
 
-→ Returns all relationships where Python appears (both as subject and object)
+→ Returns all relationships where Python appears (both as subject and object), along with its types
+
+#### Explain How Two Entities Are Connected
+
+find_path(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  from="Guido van Rossum", 
+  to="Go", 
+  undirected=true
+)
+
+→ Returns the shortest chain of triples between the two entities, such as Guido van Rossum <-[created_by]- Python -[influenced]-> Go; with max_depth it returns every chain of at most that many hops, shortest first
+
+#### Explore the Surroundings of an Entity
+
+explore_neighborhood(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  entity="Python", 
+  hops=2, 
+  max_nodes=25
+)
+
+→ Returns the entities within two hops of Python, closest first, and the triples between them; max_nodes keeps large hubs from flooding the answer, and predicates and direction restrict the triples followed
+
+#### Find the Hubs of the Graph
+
+rank_entities(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  measure="pagerank", 
+  limit=5
+)
+
+→ Returns the five most important entities with their PageRank and degrees; measure="betweenness" finds the entities bridging otherwise distant parts of the graph, and predicates restricts the triples taken into account
+
+#### Find Topical Clusters
+
+detect_communities(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  write=true
+)
+
+→ Returns the groups of densely linked entities, each named after its most representative entity; with write=true each entity gets a (entity, community, "name") triple, replacing the memberships recorded by a previous run
+
+#### Check That the Graph Holds Together
+
+graph_health(
+  knowledge_graph_path="/Users/username/knowledge.kg"
+)
+
+→ Returns the number of connected components and their sizes, lists the islands cut off from the rest of the graph, the entities linked to no other entity, the orphans without any triple and the triples linking an entity to itself
+
+#### Page Through Large Results
+
+find_triples(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  predicate="influenced", 
+  sort_by="object", 
+  limit=20, 
+  offset=20
+)
+
+→ Results always come in the same order, by subject unless sort_by says otherwise; the response gives the total number of matching triples, and offset returns the next page (describe_entity accepts the same options)
+
+### 4. Enforcing an Ontology
+
+set_schema(
+  knowledge_graph_path="/Users/username/knowledge.kg", 
+  schema="{\"enforcement\": \"strict\", \"classes\": [{\"name\": \"Person\"}, {\"name\": \"Team\"}], \"predicates\": [{\"name\": \"isMemberOf\", \"domain\": \"Person\", \"range\": \"Team\"}]}"
+)
+
+→ Declares the classes and predicates of the graph; entities are typed with insert_triple(subject="Alice", predicate="rdf:type", object="Person") and triples violating the domain or range of a predicate are rejected (or inserted with a warning when enforcement is warn)
+
+get_schema(knowledge_graph_path="/Users/username/knowledge.kg")
+
+→ Returns the active schema, to read before inserting triples
 
 ## Real-World Examples
 
 ### Building a Technology Knowledge Base
 
 insert_triple(path="/Users/username/tech.kg", subject="Python", predicate="created_by", object="Guido van Rossum")
-insert_triple(path="/Users/username/tech.kg", subject="Python", predicate="first_released", object="1991")
+insert_triple(path="/Users/username/tech.kg", subject="Python", predicate="first_released", object="1991", object_type="integer")
 insert_triple(path="/Users/username/tech.kg", subject="Django", predicate="written_in", object="Python")
 insert_triple(path="/Users/username/tech.kg", subject="Instagram", predicate="built_with", object="Django")
 
@@ -123,10 +386,14 @@ insert_triple(path="/Users/username/citations.kg", subject="Paper A", predicate=
 ## Usage Notes
 
 - Create a new .kg file or use an existing one by specifying the appropriate path
-- For best results, be consistent with naming and predicates
+- For best results, be consistent with naming and predicates; use merge_entities when the same entity was recorded under two names
 - The knowledge graph persists your data across sessions in the files you specify
 - You can build multiple specialized knowledge graphs for different domains
-- Use wildcards in find_triples by omitting parameters to get broader results`),
+- Use wildcards in find_triples by omitting parameters to get broader results
+- Use object_type for values such as numbers, dates or booleans (and object_language for translated labels) so they are stored as typed literals rather than entities
+- Use named graphs to keep facts from different sources or contexts apart; queries search every graph unless graphs is given
+- Type entities with rdf:type and organize classes with rdfs:subClassOf rather than ad hoc predicates such as is_a, so that list_instances can follow the class hierarchy
+- Give the source, agent and confidence of the facts you insert so they can be traced and reviewed later`),
 		server.WithResourceCapabilities(false, false),
 		server.WithPromptCapabilities(false),
 
@@ -137,8 +404,32 @@ insert_triple(path="/Users/username/citations.kg", subject="Paper A", predicate=
 	s.AddResourceTemplate(GetRelationFromTo(), GetRelationFromToHandler)
 	s.AddTool(InsertTriple(), InsertTripleHandler)
 	s.AddTool(RemoveTriple(), RemoveTripleHandler)
+	s.AddTool(DeleteEntity(), DeleteEntityHandler)
+	s.AddTool(RetractSource(), RetractSourceHandler)
+	s.AddTool(MergeEntities(), MergeEntitiesHandler)
+	s.AddTool(AddAlias(), AddAliasHandler)
+	s.AddTool(RemoveAlias(), RemoveAliasHandler)
+	s.AddTool(RenameEntity(), RenameEntityHandler)
+	s.AddTool(RenamePredicate(), RenamePredicateHandler)
 	s.AddTool(FindTriples(), FindTriplesHandler)
 	s.AddTool(DescribeEntity(), DescribeEntityHandler)
+	s.AddTool(ListInstances(), ListInstancesHandler)
+	s.AddTool(GetSchema(), GetSchemaHandler)
+	s.AddTool(SetSchema(), SetSchemaHandler)
+	s.AddTool(GetRules(), GetRulesHandler)
+	s.AddTool(SetRules(), SetRulesHandler)
+	s.AddTool(MaterializeInferences(), MaterializeInferencesHandler)
+	s.AddTool(DeclarePredicate(), DeclarePredicateHandler)
+	s.AddTool(ListPredicateDeclarations(), ListPredicateDeclarationsHandler)
+	s.AddTool(FindContradictions(), FindContradictionsHandler)
+	s.AddTool(CloseTriple(), CloseTripleHandler)
+	s.AddTool(GraphHistory(), GraphHistoryHandler)
+	s.AddTool(UndoLastChange(), UndoLastChangeHandler)
+	s.AddTool(FindPath(), FindPathHandler)
+	s.AddTool(ExploreNeighborhood(), ExploreNeighborhoodHandler)
+	s.AddTool(RankEntities(), RankEntitiesHandler)
+	s.AddTool(DetectCommunities(), DetectCommunitiesHandler)
+	s.AddTool(GraphHealth(), GraphHealthHandler)
 	s.AddPrompt(GetPrompt(), GetPromptHandler)
 
 	return s
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func ExploreNeighborhood() mcp.Tool {
+	return mcp.NewTool(
+		"explore_neighborhood",
+		mcp.WithDescription("Get the entities within a number of hops of an entity and the triples between them, the closest entities first, with a budget on the number of entities so that large hubs do not flood the context"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("entity",
+			mcp.Required(),
+			mcp.Description("the entity at the center of the neighborhood"),
+		),
+		mcp.WithNumber("hops",
+			mcp.Description("the maximum number of triples between the entity and the others (default 2)"),
+			mcp.Min(0),
+		),
+		mcp.WithString("predicates",
+			mcp.Description("a comma-separated list of the predicates to follow (leave empty to follow every predicate)"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("the triples followed from each entity: outgoing (to their object), incoming (to their subject) or both (default)"),
+			mcp.Enum(string(kg.DirectionOutgoing), string(kg.DirectionIncoming), string(kg.DirectionBoth)),
+		),
+		mcp.WithNumber("max_nodes",
+			mcp.Description("the maximum number of entities to return, the closest ones being kept (default 25)"),
+			mcp.Min(1),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs whose triples are followed, default naming the default graph (leave empty to follow every graph)"),
+		),
+	)
+}
+
+func ExploreNeighborhoodHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	entity := request.Params.Arguments["entity"].(string)
+
+	// Extract the optional parameters
+	hops := 2
+	if val, ok := request.Params.Arguments["hops"]; ok && val != nil {
+		hops = int(val.(float64))
+	}
+	var predicates []string
+	if val, ok := request.Params.Arguments["predicates"]; ok && val != nil {
+		predicates = predicateNames(val.(string))
+	}
+	options := kg.NeighborhoodOptions{MaxNodes: 25}
+	if val, ok := request.Params.Arguments["direction"]; ok && val != nil {
+		options.Direction = kg.Direction(val.(string))
+	}
+	if val, ok := request.Params.Arguments["max_nodes"]; ok && val != nil {
+		options.MaxNodes = int(val.(float64))
+	}
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		options.Graphs = graphNames(val.(string))
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	subgraph, err := g.Neighborhood(entity, hops, predicates, options, false)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	// List the entities by distance, then the triples between them
+	center := subgraph.Entities[0].Lexical
+	result := fmt.Sprintf("Neighborhood of %s within %d hops (%d entities, %d triples):\n", center, hops, len(subgraph.Entities), len(subgraph.Triples))
+	for distance := 1; distance <= hops; distance++ {
+		var labels []string
+		for _, node := range subgraph.Entities {
+			if subgraph.Distance[node.ID()] == distance {
+				labels = append(labels, node.Lexical)
+			}
+		}
+		if len(labels) == 0 {
+			continue
+		}
+		if distance == 1 {
+			result += "- at 1 hop: " + strings.Join(labels, ", ") + "\n"
+		} else {
+			result += fmt.Sprintf("- at %d hops: %s\n", distance, strings.Join(labels, ", "))
+		}
+	}
+	if subgraph.Truncated {
+		result += fmt.Sprintf("Limited to the %d closest entities; raise max_nodes or filter the predicates to see more.\n", options.MaxNodes)
+	}
+
+	result += "\nTriples:\n"
+	if len(subgraph.Triples) == 0 {
+		result += "- No triples found\n"
+	}
+	for _, triple := range subgraph.Triples {
+		result += "- (" + triple.F.(*kg.Node).String() + ", " + triple.Subject + ", " + triple.T.(*kg.Node).String() + ")"
+		if triple.Graph != kg.DefaultGraph {
+			result += " in graph " + triple.Graph
+		}
+		result += "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestExploreNeighborhood(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Python", "created_by", "Guido van Rossum"},
+		{"Python", "influenced", "Go"},
+		{"Go", "created_by", "Rob Pike"},
+		{"Rob Pike", "works_for", "Google"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	explore := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := ExploreNeighborhoodHandler(ctx, newCallToolRequest("explore_neighborhood", arguments))
+		if err != nil {
+			t.Fatalf("ExploreNeighborhoodHandler failed: %v", err)
+		}
+		return result
+	}
+
+	result := explore(map[string]interface{}{"entity": "go"})
+	expected := "Neighborhood of Go within 2 hops (5 entities, 4 triples):\n" +
+		"- at 1 hop: Python, Rob Pike\n" +
+		"- at 2 hops: Google, Guido van Rossum\n" +
+		"\nTriples:\n" +
+		"- (Go, created_by, Rob Pike)\n" +
+		"- (Python, created_by, Guido van Rossum)\n" +
+		"- (Python, influenced, Go)\n" +
+		"- (Rob Pike, works_for, Google)\n"
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || text != expected {
+		t.Fatalf("Expected the neighborhood of Go, got: %s", text)
+	}
+
+	result = explore(map[string]interface{}{"entity": "Go", "max_nodes": float64(2), "direction": "outgoing"})
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.Contains(text, "- at 1 hop: Rob Pike\n") || !strings.Contains(text, "Limited to the 2 closest entities") {
+		t.Fatalf("Expected a truncated neighborhood, got: %s", text)
+	}
+
+	result = explore(map[string]interface{}{"entity": "Go", "predicates": "created_by"})
+	text = result.Content[0].(mcp.TextContent).Text
+	if result.IsError || strings.Contains(text, "Python") {
+		t.Fatalf("Expected only the created_by triples, got: %s", text)
+	}
+
+	if result := explore(map[string]interface{}{"entity": "Nobody"}); !result.IsError {
+		t.Fatalf("Expected an error for an unknown entity")
+	}
+}
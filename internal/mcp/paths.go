@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+// maxPathDepth is the largest number of hops of the paths enumerated by find_path,
+// as the number of paths grows exponentially with their length.
+const maxPathDepth = 8
+
+func FindPath() mcp.Tool {
+	return mcp.NewTool(
+		"find_path",
+		mcp.WithDescription("Explain how two entities are connected: find the shortest chain of triples from one entity to the other, or every chain up to a maximum number of hops"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("the entity the paths start from"),
+		),
+		mcp.WithString("to",
+			mcp.Required(),
+			mcp.Description("the entity the paths lead to"),
+		),
+		mcp.WithNumber("max_depth",
+			mcp.Description(fmt.Sprintf("return every path of at most this number of hops instead of the shortest path only (at most %d)", maxPathDepth)),
+			mcp.Min(1),
+			mcp.Max(maxPathDepth),
+		),
+		mcp.WithNumber("max_paths",
+			mcp.Description("the maximum number of paths to return when max_depth is given, the shortest ones being kept (default 10)"),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("undirected",
+			mcp.Description("whether triples may also be followed from their object to their subject, as in Alice works_for Acme, which Bob works_for"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs whose triples may be followed, default naming the default graph (leave empty to follow every graph)"),
+		),
+	)
+}
+
+func FindPathHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	from := request.Params.Arguments["from"].(string)
+	to := request.Params.Arguments["to"].(string)
+
+	// Extract the optional parameters
+	maxDepth := 0
+	if val, ok := request.Params.Arguments["max_depth"]; ok && val != nil {
+		maxDepth = min(int(val.(float64)), maxPathDepth)
+	}
+	options := kg.PathOptions{MaxPaths: 10}
+	if val, ok := request.Params.Arguments["max_paths"]; ok && val != nil {
+		options.MaxPaths = int(val.(float64))
+	}
+	if val, ok := request.Params.Arguments["undirected"]; ok && val != nil {
+		options.Undirected = val.(bool)
+	}
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		options.Graphs = graphNames(val.(string))
+	}
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []kg.Path
+	if maxDepth > 0 {
+		paths, err = g.AllPaths(from, to, maxDepth, options, false)
+	} else {
+		var path kg.Path
+		path, err = g.ShortestPath(from, to, options, false)
+		if path != nil {
+			paths = append(paths, path)
+		}
+	}
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	if len(paths) == 0 {
+		text := fmt.Sprintf("No path found from %s to %s.", from, to)
+		if !options.Undirected {
+			text += " Try undirected=true to also follow triples from their object to their subject."
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: text,
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	// List the paths with the direction of each triple
+	result := fmt.Sprintf("Shortest path from %s to %s (%d hops):\n", from, to, len(paths[0]))
+	if maxDepth > 0 {
+		result = fmt.Sprintf("Paths from %s to %s of at most %d hops (%d):\n", from, to, maxDepth, len(paths))
+	}
+	for _, path := range paths {
+		result += "- " + path.String() + "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
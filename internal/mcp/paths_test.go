@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFindPath(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Alice", "works_for", "Acme"},
+		{"Bob", "works_for", "Acme"},
+		{"Bob", "lives_in", "Paris"},
+		{"Dave", "lives_in", "Lyon"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	findPath := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		arguments["knowledge_graph_path"] = kgPath
+		result, err := FindPathHandler(ctx, newCallToolRequest("find_path", arguments))
+		if err != nil {
+			t.Fatalf("FindPathHandler failed: %v", err)
+		}
+		return result
+	}
+
+	result := findPath(map[string]interface{}{"from": "Alice", "to": "Paris"})
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || text != "No path found from Alice to Paris. Try undirected=true to also follow triples from their object to their subject." {
+		t.Fatalf("Expected no directed path, got: %s", text)
+	}
+
+	result = findPath(map[string]interface{}{"from": "alice", "to": "paris", "undirected": true})
+	expected := "Shortest path from alice to paris (3 hops):\n- Alice -[works_for]-> Acme <-[works_for]- Bob -[lives_in]-> Paris\n"
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || text != expected {
+		t.Fatalf("Expected the shortest undirected path, got: %s", text)
+	}
+
+	result = findPath(map[string]interface{}{"from": "Alice", "to": "Bob", "undirected": true, "max_depth": float64(3)})
+	expected = "Paths from Alice to Bob of at most 3 hops (1):\n- Alice -[works_for]-> Acme <-[works_for]- Bob\n"
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || text != expected {
+		t.Fatalf("Expected every path of at most 3 hops, got: %s", text)
+	}
+
+	result = findPath(map[string]interface{}{"from": "Alice", "to": "Bob", "undirected": true, "max_depth": float64(100)})
+	expected = "Paths from Alice to Bob of at most 8 hops (1):\n- Alice -[works_for]-> Acme <-[works_for]- Bob\n"
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || text != expected {
+		t.Fatalf("Expected the maximum depth to be capped, got: %s", text)
+	}
+
+	if result := findPath(map[string]interface{}{"from": "Alice", "to": "Nobody"}); !result.IsError {
+		t.Fatalf("Expected an error for an unknown entity")
+	}
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func GetRules() mcp.Tool {
+	return mcp.NewTool(
+		"get_rules",
+		mcp.WithDescription("Get the rules of the knowledge graph, which infer new triples from the existing ones"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+	)
+}
+
+func GetRulesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := g.Rules()
+	if len(rules) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "The knowledge graph has no rules.",
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	result := "Rules:\n"
+	for _, rule := range rules {
+		result += "- " + rule.String() + "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func SetRules() mcp.Tool {
+	return mcp.NewTool(
+		"set_rules",
+		mcp.WithDescription("Replace the rules of the knowledge graph. Rules infer new triples from the existing ones; inferred triples are returned by find_triples with include_inferred, and can be stored with materialize_inferences"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("rules",
+			mcp.Required(),
+			mcp.Description(`the rules, one per line, such as "(?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z)", "is_part_of is transitive" or "cites has inverse cited_by"; a rule may be prefixed with a name and a colon, and an empty list removes every rule`),
+		),
+		mcp.WithBoolean("materialize",
+			mcp.Description("whether to store the inferred triples right away, replacing those stored earlier"),
+		),
+	)
+}
+
+func SetRulesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	text := request.Params.Arguments["rules"].(string)
+	materialize := false
+	if val, ok := request.Params.Arguments["materialize"]; ok && val != nil {
+		materialize = val.(bool)
+	}
+
+	var rules []kg.Rule
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rule, err := kg.ParseRule(line)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: err.Error(),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+		rules = append(rules, rule)
+	}
+
+	// Use the file-safe modifier function
+	var inferred int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		if err := g.SetRules(rules); err != nil {
+			return err
+		}
+		if materialize {
+			inferred = g.Materialize()
+		}
+		return nil
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	result := fmt.Sprintf("Set %d rules.", len(rules))
+	if materialize {
+		result += fmt.Sprintf(" Materialized %d inferred triples.", inferred)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func MaterializeInferences() mcp.Tool {
+	return mcp.NewTool(
+		"materialize_inferences",
+		mcp.WithDescription("Store the triples inferred by the rules of the knowledge graph, replacing those stored earlier, so that they reflect the current triples; stored inferred triples stay marked as inferred"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithBoolean("clear",
+			mcp.Description("whether to only remove the stored inferred triples, leaving the rules to be evaluated at query time"),
+		),
+	)
+}
+
+func MaterializeInferencesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	clear := false
+	if val, ok := request.Params.Arguments["clear"]; ok && val != nil {
+		clear = val.(bool)
+	}
+
+	// Use the file-safe modifier function
+	var result string
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		if clear {
+			result = fmt.Sprintf("Removed %d inferred triples.", g.ClearInferred())
+		} else {
+			result = fmt.Sprintf("Materialized %d inferred triples.", g.Materialize())
+		}
+		return nil
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
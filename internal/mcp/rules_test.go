@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRuleTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	for _, triple := range [][3]string{
+		{"Montmartre", "located_in", "Paris"},
+		{"Paris", "located_in", "France"},
+		{"Alice", "cites", "Bob"},
+	} {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              triple[0],
+			"predicate":            triple[1],
+			"object":               triple[2],
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("InsertTripleHandler failed: %v %v", err, result)
+		}
+	}
+
+	result, err := SetRulesHandler(ctx, newCallToolRequest("set_rules", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"rules":                "(?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z)\n\ncites has inverse cited_by\n",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("SetRulesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Set 2 rules." {
+		t.Fatalf("Unexpected result: %s", text)
+	}
+
+	result, err = GetRulesHandler(ctx, newCallToolRequest("get_rules", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("GetRulesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "- cites has inverse cited_by: (?x, cites, ?y) -> (?y, cited_by, ?x)") {
+		t.Fatalf("Expected the rules, got: %s", text)
+	}
+
+	findTriples := func(includeInferred bool) string {
+		result, err := FindTriplesHandler(ctx, newCallToolRequest("find_triples", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              "Montmartre",
+			"include_inferred":     includeInferred,
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	if text := findTriples(false); strings.Contains(text, "France") {
+		t.Fatalf("Expected only asserted triples, got: %s", text)
+	}
+	if text := findTriples(true); !strings.Contains(text, "- (Montmartre, located_in, France) (inferred by (?x, located_in, ?y), (?y, located_in, ?z) -> (?x, located_in, ?z))") {
+		t.Fatalf("Expected the inferred triple, got: %s", text)
+	}
+
+	result, err = MaterializeInferencesHandler(ctx, newCallToolRequest("materialize_inferences", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("MaterializeInferencesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Materialized 2 inferred triples." {
+		t.Fatalf("Unexpected result: %s", text)
+	}
+	if text := findTriples(false); strings.Contains(text, "France") {
+		t.Fatalf("Expected materialized triples to stay marked as inferred, got: %s", text)
+	}
+
+	result, err = MaterializeInferencesHandler(ctx, newCallToolRequest("materialize_inferences", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"clear":                true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("MaterializeInferencesHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Removed 2 inferred triples." {
+		t.Fatalf("Unexpected result: %s", text)
+	}
+
+	result, err = SetRulesHandler(ctx, newCallToolRequest("set_rules", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"rules":                "located_in implies near",
+	}))
+	if err != nil {
+		t.Fatalf("SetRulesHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error for an invalid rule")
+	}
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func GetSchema() mcp.Tool {
+	return mcp.NewTool(
+		"get_schema",
+		mcp.WithDescription("Get the active schema of the knowledge graph: its classes, its predicates with their domain and range, and how strictly it is enforced"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+	)
+}
+
+func GetSchemaHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+
+	// Read the graph using the thread-safe method
+	g, err := ReadKnowledgeGraph(graphPath)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := g.Schema()
+	if schema == nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "The knowledge graph has no schema.",
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+func SetSchema() mcp.Tool {
+	return mcp.NewTool(
+		"set_schema",
+		mcp.WithDescription("Replace the schema of the knowledge graph. Triples inserted afterwards are checked against it: with enforcement warn they are inserted with a warning, with enforcement strict they are rejected"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("schema",
+			mcp.Required(),
+			mcp.Description(`the schema as a JSON document, such as {"enforcement": "strict", "classes": [{"name": "Person"}, {"name": "Team"}], "predicates": [{"name": "isMemberOf", "domain": "Person", "range": "Team"}]}; a range may also be a datatype such as integer or date, and an empty document removes the schema`),
+		),
+	)
+}
+
+func SetSchemaHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	document := request.Params.Arguments["schema"].(string)
+
+	// Use the file-safe modifier function
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		var schema *kg.Schema
+		if document != "" {
+			schema = &kg.Schema{}
+			if err := json.Unmarshal([]byte(document), schema); err != nil {
+				return err
+			}
+		}
+		return g.SetSchema(schema)
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "success",
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSchemaTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	insert := func(subject, predicate, object string) *mcp.CallToolResult {
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              subject,
+			"predicate":            predicate,
+			"object":               object,
+		}))
+		if err != nil {
+			t.Fatalf("InsertTripleHandler failed: %v", err)
+		}
+		return result
+	}
+
+	insert("Alice", "rdf:type", "Person")
+
+	result, err := GetSchemaHandler(ctx, newCallToolRequest("get_schema", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("GetSchemaHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "no schema") {
+		t.Fatalf("Expected no schema, got: %s", text)
+	}
+
+	result, err = SetSchemaHandler(ctx, newCallToolRequest("set_schema", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"schema":               `{"enforcement": "warn", "classes": [{"name": "Person"}, {"name": "Team"}], "predicates": [{"name": "isMemberOf", "domain": "Person", "range": "Team"}]}`,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("SetSchemaHandler failed: %v %v", err, result)
+	}
+
+	result, err = GetSchemaHandler(ctx, newCallToolRequest("get_schema", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("GetSchemaHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, `"isMemberOf"`) || !strings.Contains(text, `"range": "Team"`) {
+		t.Fatalf("Expected the active schema, got: %s", text)
+	}
+
+	insert("Bob", "rdf:type", "Person")
+	result = insert("Alice", "isMemberOf", "Bob")
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || !strings.Contains(text, "warning: schema violation") {
+		t.Fatalf("Expected the triple to be inserted with a warning, got: %s", text)
+	}
+
+	result, err = SetSchemaHandler(ctx, newCallToolRequest("set_schema", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"schema":               `{"enforcement": "strict", "classes": [{"name": "Person"}, {"name": "Team"}], "predicates": [{"name": "isMemberOf", "domain": "Person", "range": "Team"}]}`,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("SetSchemaHandler failed: %v %v", err, result)
+	}
+	if result = insert("Bob", "isMemberOf", "Alice"); !result.IsError {
+		t.Fatalf("Expected the triple to be rejected by the strict schema")
+	}
+
+	result, err = SetSchemaHandler(ctx, newCallToolRequest("set_schema", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"schema":               `{"predicates": [{"name": "isMemberOf", "domain": "Person"}]}`,
+	}))
+	if err != nil {
+		t.Fatalf("SetSchemaHandler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected an error for a domain that is not declared")
+	}
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/owulveryck/mcpkg/internal/kg"
+)
+
+func CloseTriple() mcp.Tool {
+	return mcp.NewTool(
+		"close_triple",
+		mcp.WithDescription("Record that a fact no longer holds by ending the validity of its triple instead of removing it, so that the history remains queryable with as_of"),
+		mcp.WithString("knowledge_graph_path",
+			mcp.Required(),
+			mcp.Description("the path of the knowledge graph to interact with"),
+		),
+		mcp.WithString("subject",
+			mcp.Required(),
+			mcp.Description("the subject of the triple"),
+		),
+		mcp.WithString("predicate",
+			mcp.Required(),
+			mcp.Description("the predicate of the triple"),
+		),
+		mcp.WithString("object",
+			mcp.Description("the object of the triple (leave empty to close every current value of the predicate)"),
+		),
+		mcp.WithString("valid_to",
+			mcp.Description("the time from which the fact no longer holds, as a date (YYYY-MM-DD) or a date-time (RFC 3339); now if empty"),
+		),
+		mcp.WithString("graphs",
+			mcp.Description("a comma-separated list of the graphs holding the triple, default naming the default graph (leave empty to search every graph)"),
+		),
+	)
+}
+
+func CloseTripleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	graphPath := request.Params.Arguments["knowledge_graph_path"].(string)
+	subject := request.Params.Arguments["subject"].(string)
+	predicate := request.Params.Arguments["predicate"].(string)
+
+	// Extract the optional parameters
+	var object, validTo string
+	if val, ok := request.Params.Arguments["object"]; ok && val != nil {
+		object = val.(string)
+	}
+	if val, ok := request.Params.Arguments["valid_to"]; ok && val != nil {
+		validTo = val.(string)
+	}
+	var graphs []string
+	if val, ok := request.Params.Arguments["graphs"]; ok && val != nil {
+		graphs = graphNames(val.(string))
+	}
+
+	// Use the file-safe modifier function
+	var closed int
+	err := ModifyKnowledgeGraph(graphPath, func(g *kg.KG) error {
+		end, err := timestamp(validTo)
+		if err != nil {
+			return err
+		}
+		closed, err = g.CloseTriple(subject, predicate, object, end, false, graphs...)
+		return err
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: err.Error(),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	text := fmt.Sprintf("Closed %d triple(s).", closed)
+	if closed == 0 {
+		text = "No matching triple still holds."
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// timestamp parses a time given as a tool argument: a date-time in RFC 3339, a date-time without time zone
+// or a date, the last two being taken as UTC. An empty argument gives the zero time.
+func timestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a date (YYYY-MM-DD) or a date-time (RFC 3339)", value)
+}
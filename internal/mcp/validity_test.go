@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestValidityTools(t *testing.T) {
+	ctx := context.Background()
+	kgPath := filepath.Join(t.TempDir(), "testkg.kg")
+
+	insert := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		arguments["knowledge_graph_path"] = kgPath
+		arguments["subject"] = "Alice"
+		arguments["predicate"] = "works_for"
+		result, err := InsertTripleHandler(ctx, newCallToolRequest("insert_triple", arguments))
+		if err != nil {
+			t.Fatalf("InsertTripleHandler failed: %v", err)
+		}
+		return result
+	}
+
+	if result := insert(map[string]interface{}{"object": "Acme", "valid_from": "2018-01-01"}); result.IsError {
+		t.Fatalf("Failed to insert a triple with a validity: %v", result.Content)
+	}
+	if result := insert(map[string]interface{}{"object": "Globex", "valid_from": "2020-01-01", "valid_to": "2019-01-01"}); !result.IsError {
+		t.Fatalf("Expected an error for a period ending before it starts")
+	}
+	if result := insert(map[string]interface{}{"object": "Globex", "valid_from": "next year"}); !result.IsError {
+		t.Fatalf("Expected an error for an invalid time")
+	}
+
+	result, err := CloseTripleHandler(ctx, newCallToolRequest("close_triple", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"subject":              "Alice",
+		"predicate":            "works_for",
+		"valid_to":             "2021-01-01T00:00:00",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("CloseTripleHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Closed 1 triple(s)." {
+		t.Fatalf("Expected one closed triple, got: %s", text)
+	}
+	if result := insert(map[string]interface{}{"object": "Globex", "valid_from": "2021-01-01T00:00:00Z"}); result.IsError {
+		t.Fatalf("Failed to insert the next value: %v", result.Content)
+	}
+
+	find := func(asOf string) string {
+		arguments := map[string]interface{}{
+			"knowledge_graph_path": kgPath,
+			"subject":              "Alice",
+		}
+		if asOf != "" {
+			arguments["as_of"] = asOf
+		}
+		result, err := FindTriplesHandler(ctx, newCallToolRequest("find_triples", arguments))
+		if err != nil || result.IsError {
+			t.Fatalf("FindTriplesHandler failed: %v %v", err, result)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	text := find("")
+	if !strings.Contains(text, "(Alice, works_for, Acme) (valid from 2018-01-01T00:00:00Z to 2021-01-01T00:00:00Z)") ||
+		!strings.Contains(text, "(Alice, works_for, Globex) (valid from 2021-01-01T00:00:00Z)") {
+		t.Fatalf("Expected the whole history, got: %s", text)
+	}
+	if text := find("2019-06-01"); !strings.Contains(text, "Acme") || strings.Contains(text, "Globex") {
+		t.Fatalf("Expected only the employer in 2019, got: %s", text)
+	}
+
+	result, err = DescribeEntityHandler(ctx, newCallToolRequest("describe_entity", map[string]interface{}{
+		"knowledge_graph_path": kgPath,
+		"entity":               "Alice",
+		"as_of":                "2022-01-01",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("DescribeEntityHandler failed: %v %v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Alice works_for Globex") || strings.Contains(text, "Acme") {
+		t.Fatalf("Expected only the current employer, got: %s", text)
+	}
+}